@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNotFound = errors.New("no catalog entry found")
+
+// BookMetadata is what a catalog knows about an edition. Fields the catalog
+// does not have are left zero.
+type BookMetadata struct {
+	ISBN            string `json:"isbn"`
+	Title           string `json:"title"`
+	Author          string `json:"author"`
+	Genre           string `json:"genre"`
+	PublicationYear int    `json:"publication_year"`
+	TotalPages      int    `json:"total_pages"`
+}
+
+// CatalogProvider looks up book metadata in an external catalog.
+type CatalogProvider interface {
+	LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error)
+	SearchTitle(ctx context.Context, title string) ([]BookMetadata, error)
+}
+
+// NewProvider builds the provider named in config: "openlibrary" (default),
+// "fixture" for a local JSON file, or "none" to disable lookups.
+func NewProvider(kind string, baseURL string, fixturePath string) (CatalogProvider, error) {
+	switch kind {
+	case "", "openlibrary":
+		return NewOpenLibraryProvider(baseURL), nil
+	case "fixture":
+		return LoadFixtureProvider(fixturePath)
+	case "none":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown catalog provider %q", kind)
+}
+
+// CleanISBN strips the hyphens and spaces people type into ISBNs.
+func CleanISBN(isbn string) string {
+	var b strings.Builder
+	for _, r := range isbn {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteRune('X')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// fixtureProvider answers lookups from a fixed list of books, for tests and
+// offline development.
+type fixtureProvider struct {
+	books []BookMetadata
+}
+
+func NewFixtureProvider(books []BookMetadata) CatalogProvider {
+	return &fixtureProvider{books: books}
+}
+
+// LoadFixtureProvider reads a JSON array of BookMetadata from path.
+func LoadFixtureProvider(path string) (CatalogProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var books []BookMetadata
+	if err := json.Unmarshal(raw, &books); err != nil {
+		return nil, err
+	}
+	return NewFixtureProvider(books), nil
+}
+
+func (p *fixtureProvider) LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error) {
+	isbn = CleanISBN(isbn)
+	for _, b := range p.books {
+		if isbn != "" && CleanISBN(b.ISBN) == isbn {
+			meta := b
+			return &meta, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (p *fixtureProvider) SearchTitle(ctx context.Context, title string) ([]BookMetadata, error) {
+	needle := strings.ToLower(strings.TrimSpace(title))
+	books := []BookMetadata{}
+	for _, b := range p.books {
+		if needle != "" && strings.Contains(strings.ToLower(b.Title), needle) {
+			books = append(books, b)
+		}
+	}
+	return books, nil
+}
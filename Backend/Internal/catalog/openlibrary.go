@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultOpenLibraryURL = "https://openlibrary.org"
+
+var yearPattern = regexp.MustCompile(`\b(\d{4})\b`)
+
+type openLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpenLibraryProvider(baseURL string) CatalogProvider {
+	if baseURL == "" {
+		baseURL = defaultOpenLibraryURL
+	}
+	return &openLibraryProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+type openLibraryEdition struct {
+	Title         string            `json:"title"`
+	Authors       []openLibraryName `json:"authors"`
+	NumberOfPages int               `json:"number_of_pages"`
+	PublishDate   string            `json:"publish_date"`
+	Subjects      []openLibraryName `json:"subjects"`
+}
+
+type openLibrarySearch struct {
+	Docs []struct {
+		Title            string   `json:"title"`
+		AuthorName       []string `json:"author_name"`
+		FirstPublishYear int      `json:"first_publish_year"`
+		Pages            int      `json:"number_of_pages_median"`
+		ISBN             []string `json:"isbn"`
+		Subject          []string `json:"subject"`
+	} `json:"docs"`
+}
+
+func (p *openLibraryProvider) LookupISBN(ctx context.Context, isbn string) (*BookMetadata, error) {
+	isbn = CleanISBN(isbn)
+	if isbn == "" {
+		return nil, ErrNotFound
+	}
+
+	key := "ISBN:" + isbn
+	params := url.Values{"bibkeys": {key}, "format": {"json"}, "jscmd": {"data"}}
+	var result map[string]openLibraryEdition
+	if err := p.get(ctx, "/api/books?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	edition, ok := result[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	meta := &BookMetadata{
+		ISBN:            isbn,
+		Title:           edition.Title,
+		TotalPages:      edition.NumberOfPages,
+		PublicationYear: parseYear(edition.PublishDate),
+	}
+	if len(edition.Authors) > 0 {
+		meta.Author = edition.Authors[0].Name
+	}
+	if len(edition.Subjects) > 0 {
+		meta.Genre = edition.Subjects[0].Name
+	}
+	return meta, nil
+}
+
+func (p *openLibraryProvider) SearchTitle(ctx context.Context, title string) ([]BookMetadata, error) {
+	params := url.Values{"title": {title}, "limit": {"10"}}
+	var result openLibrarySearch
+	if err := p.get(ctx, "/search.json?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	books := make([]BookMetadata, 0, len(result.Docs))
+	for _, doc := range result.Docs {
+		meta := BookMetadata{Title: doc.Title, PublicationYear: doc.FirstPublishYear, TotalPages: doc.Pages}
+		if len(doc.AuthorName) > 0 {
+			meta.Author = doc.AuthorName[0]
+		}
+		if len(doc.ISBN) > 0 {
+			meta.ISBN = doc.ISBN[0]
+		}
+		if len(doc.Subject) > 0 {
+			meta.Genre = doc.Subject[0]
+		}
+		books = append(books, meta)
+	}
+	return books, nil
+}
+
+func (p *openLibraryProvider) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("catalog request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("catalog returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// parseYear pulls the year out of free-form dates like "September 21, 1937".
+func parseYear(date string) int {
+	match := yearPattern.FindString(date)
+	if match == "" {
+		return 0
+	}
+	year, _ := strconv.Atoi(match)
+	return year
+}
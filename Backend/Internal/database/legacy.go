@@ -0,0 +1,180 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+	"gorm.io/gorm"
+)
+
+// upgradeLegacySchema brings a database created before versioned migrations
+// up to the baseline migration. These are the AutoMigrate call and backfills
+// the server used to run on every boot; they only run once, when such a
+// database is adopted.
+func upgradeLegacySchema(db *gorm.DB) error {
+	// goals used to be unique per (user, year, month) only
+	if db.Migrator().HasIndex(&models.ReadingGoal{}, "idx_user_year_month") {
+		if err := db.Migrator().DropIndex(&models.ReadingGoal{}, "idx_user_year_month"); err != nil {
+			return fmt.Errorf("failed to drop old goal index: %w", err)
+		}
+	}
+
+	// book titles used to be unique across all users; AutoMigrate only checks
+	// the index name, so drop it to have it rebuilt per user
+	if db.Migrator().HasIndex(&models.Book{}, "idx_user_title_author") {
+		if err := db.Migrator().DropIndex(&models.Book{}, "idx_user_title_author"); err != nil {
+			return fmt.Errorf("failed to drop old book index: %w", err)
+		}
+	}
+
+	// reviews used to be owned only through their book; add the author before
+	// AutoMigrate so the NOT NULL column can be filled in for existing rows
+	if db.Migrator().HasTable(&models.Review{}) && !db.Migrator().HasColumn(&models.Review{}, "UserID") {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			steps := []string{
+				`ALTER TABLE reviews ADD COLUMN user_id bigint`,
+				`UPDATE reviews SET user_id = books.user_id FROM books WHERE books.id = reviews.book_id`,
+				`ALTER TABLE reviews ALTER COLUMN user_id SET NOT NULL`,
+			}
+			for _, step := range steps {
+				if err := tx.Exec(step).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to backfill review authors: %w", err)
+		}
+	}
+
+	err := db.AutoMigrate(
+		&models.User{},
+		&models.Book{},
+		&models.ReadingProgress{},
+		&models.Review{},
+		&models.ReadingGoal{},
+		&models.ReadingSession{},
+		&models.RefreshToken{},
+		&models.Shelf{},
+		&models.Tag{},
+		&models.Work{},
+		&models.Edition{},
+		&models.ReviewVote{},
+		&models.ReviewRevision{},
+		&models.Follow{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	// monthly goals created before goal periods existed
+	err = db.Exec(`UPDATE reading_goals
+		SET start_date = make_date(year, month, 1),
+			end_date = (make_date(year, month, 1) + INTERVAL '1 month' - INTERVAL '1 day')::date
+		WHERE start_date IS NULL AND type = 'monthly' AND month BETWEEN 1 AND 12`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill goal periods: %w", err)
+	}
+
+	// finish dates used to be inferred from last_updated
+	err = db.Exec(`UPDATE reading_progresses
+		SET finished_at = last_updated
+		WHERE status = 'Finished' AND finished_at IS NULL`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill finish dates: %w", err)
+	}
+	err = db.Exec(`UPDATE reading_progresses
+		SET started_at = (SELECT MIN(reading_sessions.started_at) FROM reading_sessions WHERE reading_sessions.book_id = reading_progresses.book_id)
+		WHERE status <> 'Want to Read' AND started_at IS NULL`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill start dates: %w", err)
+	}
+
+	// statuses were free text before the enum; map anything unknown to the closest valid one
+	err = db.Exec(`UPDATE reading_progresses
+		SET status = CASE WHEN current_page > 0 THEN 'Currently Reading' ELSE 'Want to Read' END
+		WHERE status NOT IN ('Want to Read', 'Currently Reading', 'Paused', 'Finished', 'Did Not Finish')`).Error
+	if err != nil {
+		return fmt.Errorf("failed to normalize reading statuses: %w", err)
+	}
+
+	// reviews written before re-reads existed belong to the book's only read-through
+	err = db.Exec(`UPDATE reviews
+		SET reading_progress_id = (SELECT reading_progresses.id FROM reading_progresses WHERE reading_progresses.book_id = reviews.book_id AND reading_progresses.is_current)
+		WHERE reading_progress_id IS NULL`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill review read-throughs: %w", err)
+	}
+
+	if err := normalizeISBNs(db); err != nil {
+		return fmt.Errorf("failed to normalize ISBNs: %w", err)
+	}
+
+	if err := linkBooksToWorks(db); err != nil {
+		return fmt.Errorf("failed to link books to the shared catalog: %w", err)
+	}
+	return nil
+}
+
+// normalizeISBNs rewrites ISBNs saved before validation existed as bare
+// ISBN-13. Values that fail the checksum are left for the user to fix.
+func normalizeISBNs(db *gorm.DB) error {
+	var books []models.Book
+	err := db.Select("id", "isbn").
+		Where("isbn <> '' AND isbn !~ '^97[89][0-9]{10}$'").
+		Find(&books).Error
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, book := range books {
+		isbn, err := utils.NormalizeISBN(book.ISBN)
+		if err != nil {
+			invalid++
+			continue
+		}
+		if err := db.Model(&models.Book{}).Where("id = ?", book.ID).Update("isbn", isbn).Error; err != nil {
+			return err
+		}
+	}
+	if invalid > 0 {
+		log.Printf("%d books have an ISBN that is not valid and were left unchanged", invalid)
+	}
+	return nil
+}
+
+// linkBooksToWorks builds the shared catalog from library entries added
+// before it existed. The work key matches services.WorkKey.
+func linkBooksToWorks(db *gorm.DB) error {
+	const workKey = `LOWER(REGEXP_REPLACE(TRIM(books.title), '\s+', ' ', 'g')) || '|' || LOWER(REGEXP_REPLACE(TRIM(books.author), '\s+', ' ', 'g'))`
+
+	steps := []string{
+		// editions first, so a known ISBN decides the work like it does on add
+		`UPDATE books SET work_id = editions.work_id, edition_id = editions.id
+			FROM editions WHERE books.work_id IS NULL AND books.isbn <> '' AND editions.isbn = books.isbn`,
+		`INSERT INTO works (key, title, author, genre, created_at)
+			SELECT DISTINCT ON (` + workKey + `) ` + workKey + `, TRIM(books.title), TRIM(books.author), books.genre, NOW()
+			FROM books WHERE books.work_id IS NULL
+			ORDER BY ` + workKey + `, books.created_at
+			ON CONFLICT (key) DO NOTHING`,
+		`UPDATE books SET work_id = works.id
+			FROM works WHERE books.work_id IS NULL AND works.key = ` + workKey,
+		`INSERT INTO editions (work_id, isbn, publication_year, total_pages, created_at)
+			SELECT DISTINCT ON (books.isbn) books.work_id, books.isbn, books.publication_year, books.total_pages, NOW()
+			FROM books WHERE books.edition_id IS NULL AND books.isbn <> '' AND books.work_id IS NOT NULL
+			ORDER BY books.isbn, books.created_at
+			ON CONFLICT (isbn) DO NOTHING`,
+		`UPDATE books SET edition_id = editions.id
+			FROM editions WHERE books.edition_id IS NULL AND books.isbn <> '' AND editions.isbn = books.isbn`,
+	}
+	for _, step := range steps {
+		if err := db.Exec(step).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
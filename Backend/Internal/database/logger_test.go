@@ -0,0 +1,90 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func traceLine(t *testing.T, level slog.Level, begin time.Time, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	ctx := logging.WithLogger(context.Background(), logging.New(&buf, level).With("request_id", "abc"))
+
+	queryLogger{}.Trace(ctx, begin, func() (string, int64) {
+		return `SELECT * FROM "books" WHERE id = $1`, 1
+	}, err)
+
+	if buf.Len() == 0 {
+		return nil
+	}
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q", buf.String())
+	}
+	return line
+}
+
+func TestQueryLogger_FailedQuery(t *testing.T) {
+	line := traceLine(t, slog.LevelInfo, time.Now(), errors.New("connection refused"))
+	if line == nil || line["level"] != "ERROR" || line["msg"] != "query failed" {
+		t.Fatalf("Expected an error line, got %v", line)
+	}
+	if line["request_id"] != "abc" || line["error"] != "connection refused" || line["sql"] == "" {
+		t.Errorf("Expected the request ID, error and SQL, got %v", line)
+	}
+}
+
+func TestQueryLogger_SlowQuery(t *testing.T) {
+	line := traceLine(t, slog.LevelInfo, time.Now().Add(-time.Second), nil)
+	if line == nil || line["level"] != "WARN" || line["msg"] != "slow query" {
+		t.Errorf("Expected a slow query warning, got %v", line)
+	}
+}
+
+func TestQueryLogger_RecordNotFoundIsNotAnError(t *testing.T) {
+	if line := traceLine(t, slog.LevelInfo, time.Now(), gorm.ErrRecordNotFound); line != nil {
+		t.Errorf("Expected nothing at info for a missing record, got %v", line)
+	}
+}
+
+func TestQueryLogger_QueriesOnlyAtDebug(t *testing.T) {
+	if line := traceLine(t, slog.LevelInfo, time.Now(), nil); line != nil {
+		t.Errorf("Expected nothing at info for a fast query, got %v", line)
+	}
+	if line := traceLine(t, slog.LevelDebug, time.Now(), nil); line == nil || line["msg"] != "query" {
+		t.Errorf("Expected the query at debug, got %v", line)
+	}
+}
+
+func TestQueryLogger_NeverLogsBoundValues(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.WithLogger(context.Background(), logging.New(&buf, slog.LevelDebug))
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 queryLogger{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.WithContext(ctx).Exec("UPDATE users SET password = ? WHERE id = ?", "hunter2-hash", 1)
+
+	if !strings.Contains(buf.String(), "UPDATE users") {
+		t.Fatalf("Expected the query to be logged at debug, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "hunter2-hash") {
+		t.Errorf("Expected bound values to be left out, got %q", buf.String())
+	}
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock serialises migrations when several instances boot at once.
+const migrationLock = 0x6d696772
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a pair of embedded files, migrations/NNNN_name.up.sql and
+// migrations/NNNN_name.down.sql, applied in version order.
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationState is a known migration and when it was applied, if it was.
+type MigrationState struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		parts := migrationFileName.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(parts[1], 10, 64)
+		body, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.Name != parts[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, parts[2])
+		}
+		if parts[3] == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// ensureMigrationsTable creates schema_migrations. A database that already has
+// tables but no schema_migrations predates versioned migrations: it is brought
+// up to the baseline once and the baseline is recorded as applied.
+func ensureMigrationsTable(db *gorm.DB, migrations []Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+			return err
+		}
+		if tx.Migrator().HasTable(&schemaMigration{}) {
+			return nil
+		}
+
+		legacy := tx.Migrator().HasTable("users")
+		if err := tx.Exec(`CREATE TABLE schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT NOW()
+		)`).Error; err != nil {
+			return err
+		}
+		if !legacy || len(migrations) == 0 {
+			return nil
+		}
+
+		baseline := migrations[0]
+		log.Printf("Upgrading a database from before versioned migrations to %04d_%s", baseline.Version, baseline.Name)
+		if err := upgradeLegacySchema(tx); err != nil {
+			return err
+		}
+		if err := tx.Exec(baseline.up).Error; err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", baseline.Version, baseline.Name, err)
+		}
+		return tx.Create(&schemaMigration{Version: baseline.Version, Name: baseline.Name, AppliedAt: time.Now()}).Error
+	})
+}
+
+// MigrateUp applies every pending migration, each in its own transaction,
+// and returns how many were applied.
+func MigrateUp(db *gorm.DB) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if err := ensureMigrationsTable(db, migrations); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+				return err
+			}
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", m.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := tx.Exec(m.up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		if ran {
+			applied++
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
+	}
+	return applied, nil
+}
+
+// MigrateDown rolls back the most recently applied migrations, newest first.
+func MigrateDown(db *gorm.DB, steps int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if err := ensureMigrationsTable(db, migrations); err != nil {
+		return err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	for i := 0; i < steps; i++ {
+		var rolledBack *Migration
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+				return err
+			}
+			var latest schemaMigration
+			err := tx.Order("version DESC").Limit(1).Find(&latest).Error
+			if err != nil || latest.Version == 0 {
+				return err
+			}
+			m, ok := byVersion[latest.Version]
+			if !ok {
+				return fmt.Errorf("applied migration %04d_%s is not known to this binary", latest.Version, latest.Name)
+			}
+			if err := tx.Exec(m.down).Error; err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			rolledBack = &m
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return err
+		}
+		if rolledBack == nil {
+			return nil
+		}
+		log.Printf("Rolled back migration %04d_%s", rolledBack.Version, rolledBack.Name)
+	}
+	return nil
+}
+
+// MigrationStatus lists every migration known to the binary, plus any applied
+// migration it does not know about.
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []schemaMigration
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		if err := db.Order("version").Find(&applied).Error; err != nil {
+			return nil, err
+		}
+	}
+	appliedAt := make(map[int64]schemaMigration, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if a, ok := appliedAt[m.Version]; ok {
+			at := a.AppliedAt
+			state.AppliedAt = &at
+			delete(appliedAt, m.Version)
+		}
+		states = append(states, state)
+	}
+	for _, a := range appliedAt {
+		at := a.AppliedAt
+		states = append(states, MigrationState{Version: a.Version, Name: a.Name, AppliedAt: &at})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS review_revisions;
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS book_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS book_shelves;
+DROP TABLE IF EXISTS shelves;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS reading_sessions;
+DROP TABLE IF EXISTS reading_goals;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS reading_progresses;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS editions;
+DROP TABLE IF EXISTS works;
+DROP TABLE IF EXISTS users;
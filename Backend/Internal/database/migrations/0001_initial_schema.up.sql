@@ -0,0 +1,192 @@
+-- Baseline: the whole schema at the switch to versioned migrations. A database
+-- from an AutoMigrate-based release is first brought up to it by
+-- upgradeLegacySchema (database/legacy.go).
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    display_name text,
+    default_review_visibility text NOT NULL DEFAULT 'public',
+    email text NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS works (
+    id bigserial PRIMARY KEY,
+    key text NOT NULL,
+    title text NOT NULL,
+    author text NOT NULL,
+    genre text,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_works_key ON works (key);
+
+CREATE TABLE IF NOT EXISTS editions (
+    id bigserial PRIMARY KEY,
+    work_id bigint NOT NULL CONSTRAINT fk_editions_work REFERENCES works (id) ON DELETE CASCADE,
+    isbn text NOT NULL,
+    publication_year bigint,
+    total_pages bigint,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_editions_work_id ON editions (work_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_editions_isbn ON editions (isbn);
+
+CREATE TABLE IF NOT EXISTS books (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL CONSTRAINT fk_books_user REFERENCES users (id) ON DELETE CASCADE,
+    title text NOT NULL,
+    author text NOT NULL,
+    isbn text,
+    work_id bigint CONSTRAINT fk_books_work REFERENCES works (id) ON DELETE SET NULL,
+    edition_id bigint CONSTRAINT fk_books_edition REFERENCES editions (id) ON DELETE SET NULL,
+    genre text,
+    publication_year bigint,
+    total_pages bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_title_author ON books (user_id, title, author);
+CREATE INDEX IF NOT EXISTS idx_books_work_id ON books (work_id);
+CREATE INDEX IF NOT EXISTS idx_books_edition_id ON books (edition_id);
+
+CREATE TABLE IF NOT EXISTS reading_progresses (
+    id bigserial PRIMARY KEY,
+    book_id bigint NOT NULL CONSTRAINT fk_reading_progresses_book REFERENCES books (id) ON DELETE CASCADE,
+    read_number bigint NOT NULL DEFAULT 1,
+    is_current boolean NOT NULL DEFAULT true,
+    current_page bigint DEFAULT 0,
+    status text DEFAULT 'Want to Read',
+    started_at timestamptz,
+    finished_at timestamptz,
+    last_updated timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_book_read_number ON reading_progresses (book_id, read_number);
+CREATE INDEX IF NOT EXISTS idx_reading_progresses_is_current ON reading_progresses (is_current);
+CREATE INDEX IF NOT EXISTS idx_reading_progresses_finished_at ON reading_progresses (finished_at);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id bigserial PRIMARY KEY,
+    book_id bigint NOT NULL CONSTRAINT fk_reviews_book REFERENCES books (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL CONSTRAINT fk_reviews_user REFERENCES users (id) ON DELETE CASCADE,
+    reading_progress_id bigint CONSTRAINT fk_reading_progresses_review REFERENCES reading_progresses (id) ON DELETE SET NULL,
+    rating bigint,
+    comment text,
+    visibility text NOT NULL DEFAULT 'public',
+    is_spoiler boolean NOT NULL DEFAULT false,
+    created_at timestamptz,
+    edited_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_reviews_user_id ON reviews (user_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_reading_progress_id ON reviews (reading_progress_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_visibility ON reviews (visibility);
+
+CREATE TABLE IF NOT EXISTS reading_goals (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    type text NOT NULL DEFAULT 'monthly',
+    metric text NOT NULL DEFAULT 'books',
+    year bigint NOT NULL,
+    month bigint NOT NULL,
+    week bigint NOT NULL DEFAULT 0,
+    start_date date,
+    end_date date,
+    target_books bigint NOT NULL,
+    target_pages bigint,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_goal_period ON reading_goals (user_id, type, metric, start_date, end_date);
+
+CREATE TABLE IF NOT EXISTS reading_sessions (
+    id bigserial PRIMARY KEY,
+    book_id bigint NOT NULL CONSTRAINT fk_reading_sessions_book REFERENCES books (id) ON DELETE CASCADE,
+    start_page bigint NOT NULL,
+    end_page bigint NOT NULL,
+    started_at timestamptz NOT NULL,
+    ended_at timestamptz NOT NULL,
+    duration_minutes bigint,
+    note text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_reading_sessions_book_id ON reading_sessions (book_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL CONSTRAINT fk_refresh_tokens_user REFERENCES users (id) ON DELETE CASCADE,
+    session_id text NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS shelves (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL CONSTRAINT fk_shelves_user REFERENCES users (id) ON DELETE CASCADE,
+    name text NOT NULL,
+    description text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_shelf_name ON shelves (user_id, name);
+
+CREATE TABLE IF NOT EXISTS book_shelves (
+    book_id bigint CONSTRAINT fk_book_shelves_book REFERENCES books (id),
+    shelf_id bigint CONSTRAINT fk_book_shelves_shelf REFERENCES shelves (id),
+    PRIMARY KEY (book_id, shelf_id)
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL CONSTRAINT fk_tags_user REFERENCES users (id) ON DELETE CASCADE,
+    name text NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_tag_name ON tags (user_id, name);
+
+CREATE TABLE IF NOT EXISTS book_tags (
+    book_id bigint CONSTRAINT fk_book_tags_book REFERENCES books (id),
+    tag_id bigint CONSTRAINT fk_book_tags_tag REFERENCES tags (id),
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    id bigserial PRIMARY KEY,
+    review_id bigint NOT NULL CONSTRAINT fk_review_votes_review REFERENCES reviews (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL CONSTRAINT fk_review_votes_user REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_voter ON review_votes (review_id, user_id);
+
+CREATE TABLE IF NOT EXISTS review_revisions (
+    id bigserial PRIMARY KEY,
+    review_id bigint NOT NULL CONSTRAINT fk_review_revisions_review REFERENCES reviews (id) ON DELETE CASCADE,
+    rating bigint,
+    comment text,
+    written_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_review_revisions_review_id ON review_revisions (review_id);
+
+CREATE TABLE IF NOT EXISTS follows (
+    id bigserial PRIMARY KEY,
+    follower_id bigint NOT NULL CONSTRAINT fk_follows_follower REFERENCES users (id) ON DELETE CASCADE,
+    followee_id bigint NOT NULL CONSTRAINT fk_follows_followee REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_follow_pair ON follows (follower_id, followee_id);
+CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows (followee_id);
+
+-- Search indexes. The expressions must stay identical to the ones in
+-- repository/book_search.go, otherwise Postgres will not use them.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN ((setweight(to_tsvector('english', coalesce(books.title, '')), 'A') || setweight(to_tsvector('english', coalesce(books.author, '')), 'A') || setweight(to_tsvector('english', coalesce(books.genre, '')), 'C')));
+CREATE INDEX IF NOT EXISTS idx_reviews_search ON reviews USING GIN ((to_tsvector('english', coalesce(reviews.comment, ''))));
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_author_trgm ON books USING GIN (author gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_isbn_digits ON books ((regexp_replace(books.isbn, '[^0-9Xx]', '', 'g')));
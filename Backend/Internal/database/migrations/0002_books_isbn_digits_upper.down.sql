@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_books_isbn_digits;
+CREATE INDEX idx_books_isbn_digits ON books ((regexp_replace(books.isbn, '[^0-9Xx]', '', 'g')));
@@ -0,0 +1,4 @@
+-- Search compares UPPER(...) with the normalized query, so the index has to be
+-- on that exact expression (repository/book_search.go isbnDigits).
+DROP INDEX IF EXISTS idx_books_isbn_digits;
+CREATE INDEX idx_books_isbn_digits ON books ((UPPER(regexp_replace(books.isbn, '[^0-9Xx]', '', 'g'))));
@@ -0,0 +1,5 @@
+-- Following someone used to grant access to their followers-only reviews
+-- straight away. A follow is now a request the followee has to approve;
+-- existing follows were never approved, so they start out pending. A database
+-- adopted from AutoMigrate already has the column.
+ALTER TABLE follows ADD COLUMN IF NOT EXISTS approved boolean NOT NULL DEFAULT false;
@@ -1,10 +1,9 @@
 package database
 
 import (
-	"fmt"
 	"log"
 
-	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/configs"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,24 +12,31 @@ import (
 var DB *gorm.DB
 
 func ConnectDB(cfg *configs.Config) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
-
 	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{Logger: queryLogger{}})
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
+	if err := DB.Use(metrics.GormPlugin{}); err != nil {
+		log.Fatal("Failed to set up query metrics: ", err)
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		log.Fatal("Failed to configure database pool: ", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime.Duration)
+
+	log.Println("Database connection successful")
+}
 
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Book{},
-		&models.ReadingProgress{},
-		&models.Review{},
-		&models.ReadingGoal{},
-	)
+// Close releases the connection pool once the server has stopped.
+func Close() error {
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatal("Failed to migrate database: ", err)
+		return err
 	}
-	log.Println("Database connection successful and 5 Tables created!")
+	return sqlDB.Close()
 }
@@ -1,8 +1,16 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+// CreateBookRequest needs a title and author unless an ISBN is given, in which
+// case missing details are filled in from the catalog.
 type CreateBookRequest struct {
-	Title           string `json:"title" binding:"required"`
-	Author          string `json:"author" binding:"required"`
+	Title           string `json:"title" binding:"required_without=ISBN"`
+	Author          string `json:"author" binding:"required_without=ISBN"`
 	ISBN            string `json:"isbn"`
 	Genre           string `json:"genre"`
 	PublicationYear int    `json:"publication_year"`
@@ -14,3 +22,34 @@ type UpdateBookRequest struct {
 	Author     string `json:"author"`
 	TotalPages int    `json:"total_pages"`
 }
+
+type BookListQuery struct {
+	Cursor   string `form:"cursor"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=title author created_at last_updated rating"`
+	Order    string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Genre    string `form:"genre"`
+	Status   string `form:"status"`
+	Author   string `form:"author"`
+	YearFrom int    `form:"year_from"`
+	YearTo   int    `form:"year_to"`
+	Shelf    uint   `form:"shelf"`
+	Tag      string `form:"tag"`
+}
+
+type ReadThrough struct {
+	ID          uint                 `json:"id"`
+	ReadNumber  int                  `json:"read_number"`
+	IsCurrent   bool                 `json:"is_current"`
+	Status      models.ReadingStatus `json:"status"`
+	CurrentPage int                  `json:"current_page"`
+	StartedAt   *time.Time           `json:"started_at"`
+	FinishedAt  *time.Time           `json:"finished_at"`
+	Rating      int                  `json:"rating,omitempty"`
+	Review      string               `json:"review,omitempty"`
+}
+
+type BookDetail struct {
+	models.Book
+	ReadHistory []ReadThrough `json:"read_history"`
+}
@@ -4,6 +4,8 @@ type DashboardStats struct {
 	TotalBooks       int64 `json:"total_books"`
 	BooksFinished    int64 `json:"books_finished"`
 	CurrentlyReading int64 `json:"currently_reading"`
+	Paused           int64 `json:"paused"`
+	DidNotFinish     int64 `json:"did_not_finish"`
 	YearlyTarget     int   `json:"yearly_target"`
 	MonthlyTarget    int   `json:"monthly_target"`
 	MonthlyFinished  int64 `json:"monthly_finished"`
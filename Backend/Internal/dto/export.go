@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+// ExportBook is one library entry. The status, dates and review describe the
+// current read-through; ReadHistory lists every read-through and ReadCount
+// counts the finished ones.
+type ExportBook struct {
+	ID              uint                 `json:"id"`
+	Title           string               `json:"title"`
+	Author          string               `json:"author"`
+	ISBN            string               `json:"isbn"`
+	Genre           string               `json:"genre"`
+	PublicationYear int                  `json:"publication_year"`
+	TotalPages      int                  `json:"total_pages"`
+	AddedAt         time.Time            `json:"added_at"`
+	Status          models.ReadingStatus `json:"status"`
+	CurrentPage     int                  `json:"current_page"`
+	StartedAt       *time.Time           `json:"started_at,omitempty"`
+	FinishedAt      *time.Time           `json:"finished_at,omitempty"`
+	LastUpdated     *time.Time           `json:"last_updated,omitempty"`
+	Rating          int                  `json:"rating,omitempty"`
+	Review          string               `json:"review,omitempty"`
+	ReviewedAt      *time.Time           `json:"reviewed_at,omitempty"`
+	ReadCount       int                  `json:"read_count"`
+	ReadHistory     []ReadThrough        `json:"read_history"`
+}
+
+type ExportGoal struct {
+	Type        string `json:"type"`
+	Metric      string `json:"metric"`
+	Year        int    `json:"year"`
+	Month       int    `json:"month,omitempty"`
+	Week        int    `json:"week,omitempty"`
+	StartDate   string `json:"start_date,omitempty"`
+	EndDate     string `json:"end_date,omitempty"`
+	TargetBooks int    `json:"target_books,omitempty"`
+	TargetPages int    `json:"target_pages,omitempty"`
+}
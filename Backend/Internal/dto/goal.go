@@ -1,15 +1,27 @@
 package dto
 
 type SetGoalRequest struct {
-	Year        int `json:"year" binding:"required"`
-	Month       int `json:"month" binding:"required,min=1,max=12"`
-	TargetBooks int `json:"target_books" binding:"required,min=1"`
+	Type        string `json:"type" binding:"omitempty,oneof=monthly yearly weekly custom"`
+	Metric      string `json:"metric" binding:"omitempty,oneof=books pages"`
+	Year        int    `json:"year"`
+	Month       int    `json:"month" binding:"omitempty,min=1,max=12"`
+	Week        int    `json:"week" binding:"omitempty,min=1,max=53"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	TargetBooks int    `json:"target_books" binding:"omitempty,min=1"`
+	TargetPages int    `json:"target_pages" binding:"omitempty,min=1"`
 }
 
 type GoalProgressResponse struct {
-	Year        int  `json:"year"`
-	Month       int  `json:"month"`
-	Target      int  `json:"target"`
-	Current     int  `json:"current"`
-	IsCompleted bool `json:"is_completed"`
+	ID          uint   `json:"id"`
+	Type        string `json:"type"`
+	Metric      string `json:"metric"`
+	Year        int    `json:"year"`
+	Month       int    `json:"month"`
+	Week        int    `json:"week,omitempty"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Target      int    `json:"target"`
+	Current     int    `json:"current"`
+	IsCompleted bool   `json:"is_completed"`
 }
@@ -0,0 +1,18 @@
+package dto
+
+type ImportRowResult struct {
+	Row     int    `json:"row"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	Action  string `json:"action"`
+	BookID  uint   `json:"book_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type ImportReport struct {
+	Created int               `json:"created"`
+	Merged  int               `json:"merged"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
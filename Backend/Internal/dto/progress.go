@@ -1,6 +1,21 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
 type UpdateProgressRequest struct {
-	CurrentPage int    `json:"current_page" binding:"min=0"`
-	Status      string `json:"status" binding:"required"`
+	CurrentPage int                  `json:"current_page" binding:"min=0"`
+	Status      models.ReadingStatus `json:"status" binding:"required"`
+
+	// optional session details, recorded alongside the bookmark
+	StartedAt *time.Time `json:"started_at"`
+	Note      string     `json:"note"`
+}
+
+type UpdateProgressDatesRequest struct {
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
 }
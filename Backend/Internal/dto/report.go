@@ -0,0 +1,33 @@
+package dto
+
+type ReportBook struct {
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Pages      int    `json:"pages"`
+	Rating     int    `json:"rating,omitempty"`
+	FinishedOn string `json:"finished_on"`
+}
+
+// GoalHitRate covers the monthly goals of the year that are already decided:
+// the month is over or the target was reached early.
+type GoalHitRate struct {
+	MonthsWithGoal int     `json:"months_with_goal"`
+	MonthsHit      int     `json:"months_hit"`
+	HitRate        float64 `json:"hit_rate"`
+}
+
+// YearReport recaps a calendar year. PagesRead counts the pages of reading
+// sessions logged that year, the same way stats and page goals do.
+type YearReport struct {
+	Year          int              `json:"year"`
+	BooksFinished int              `json:"books_finished"`
+	PagesRead     int64            `json:"pages_read"`
+	AverageRating *float64         `json:"average_rating"`
+	LongestBook   *ReportBook      `json:"longest_book"`
+	ShortestBook  *ReportBook      `json:"shortest_book"`
+	TopGenres     []StatsBreakdown `json:"top_genres"`
+	TopAuthors    []StatsBreakdown `json:"top_authors"`
+	HighestRated  []ReportBook     `json:"highest_rated"`
+	BooksPerMonth []MonthlyBooks   `json:"books_per_month"`
+	MonthlyGoals  GoalHitRate      `json:"monthly_goals"`
+}
@@ -1,6 +1,83 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
 type CreateReviewRequest struct {
-	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
-	Comment string `json:"comment"`
+	Rating     int                     `json:"rating" binding:"required,min=1,max=5"`
+	Comment    string                  `json:"comment"`
+	Visibility models.ReviewVisibility `json:"visibility" binding:"omitempty,oneof=private followers public"`
+	IsSpoiler  bool                    `json:"is_spoiler"`
+}
+
+// UpdateReviewRequest leaves visibility and the spoiler flag alone when they
+// are omitted.
+type UpdateReviewRequest struct {
+	Rating     int                     `json:"rating" binding:"required,min=1,max=5"`
+	Comment    string                  `json:"comment"`
+	Visibility models.ReviewVisibility `json:"visibility" binding:"omitempty,oneof=private followers public"`
+	IsSpoiler  *bool                   `json:"is_spoiler"`
+}
+
+type ReviewFeedQuery struct {
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	Sort  string `form:"sort" binding:"omitempty,oneof=newest helpful"`
+	// ShowSpoilers returns spoiler comments instead of hiding them
+	ShowSpoilers bool `form:"show_spoilers"`
+}
+
+// PublicReview is a review as other readers see it: no user object, only the
+// reviewer's display name.
+type PublicReview struct {
+	ID           uint   `json:"id"`
+	Rating       int    `json:"rating"`
+	Comment      string `json:"comment"`
+	ReviewerID   uint   `json:"reviewer_id"`
+	ReviewerName string `json:"reviewer_name"`
+	IsSpoiler    bool   `json:"is_spoiler"`
+	// SpoilerHidden means Comment was withheld; ask again with show_spoilers=true
+	SpoilerHidden bool       `json:"spoiler_hidden" gorm:"-"`
+	EditedAt      *time.Time `json:"edited_at"`
+	HelpfulCount  int64      `json:"helpful_count"`
+	IsMine        bool       `json:"is_mine"`
+	VotedHelpful  bool       `json:"voted_helpful"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ReviewSummary struct {
+	AverageRating float64       `json:"average_rating"`
+	RatingCount   int64         `json:"rating_count"`
+	Histogram     map[int]int64 `json:"histogram"`
+}
+
+type ReviewFeed struct {
+	Summary ReviewSummary  `json:"summary"`
+	Reviews []PublicReview `json:"data"`
+	Page    int            `json:"page"`
+	Limit   int            `json:"limit"`
+	HasMore bool           `json:"has_more"`
+}
+
+type ReviewedBook struct {
+	ID     uint   `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	ISBN   string `json:"isbn"`
+	WorkID *uint  `json:"work_id"`
+}
+
+type MyReview struct {
+	ID            uint                    `json:"id"`
+	Rating        int                     `json:"rating"`
+	Comment       string                  `json:"comment"`
+	ReadThroughID *uint                   `json:"read_through_id"`
+	Visibility    models.ReviewVisibility `json:"visibility"`
+	IsSpoiler     bool                    `json:"is_spoiler"`
+	CreatedAt     time.Time               `json:"created_at"`
+	EditedAt      *time.Time              `json:"edited_at"`
+	Book          ReviewedBook            `json:"book"`
 }
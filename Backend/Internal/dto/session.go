@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+type CreateSessionRequest struct {
+	StartPage int       `json:"start_page" binding:"min=0"`
+	EndPage   int       `json:"end_page" binding:"required,min=0"`
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Note      string    `json:"note"`
+}
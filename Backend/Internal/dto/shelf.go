@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type ShelfRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type ShelfBooksRequest struct {
+	BookIDs []uint `json:"book_ids" binding:"required,min=1"`
+}
+
+type TagBooksRequest struct {
+	BookIDs []uint   `json:"book_ids" binding:"required,min=1"`
+	Tags    []string `json:"tags" binding:"required,min=1,dive,required,max=50"`
+}
+
+type ShelfSummary struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	BookCount   int64     `json:"book_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TagSummary struct {
+	Name      string `json:"name"`
+	BookCount int64  `json:"book_count"`
+}
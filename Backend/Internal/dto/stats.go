@@ -0,0 +1,69 @@
+package dto
+
+import "time"
+
+// StatsQuery is an inclusive date range (YYYY-MM-DD); both ends are optional.
+type StatsQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// FinishedRead is one completed read-through with the book details used for
+// breakdowns. Rating is 0 when that read was not reviewed.
+type FinishedRead struct {
+	BookID     uint
+	Title      string
+	Author     string
+	Genre      string
+	TotalPages int
+	Rating     int
+	StartedAt  *time.Time
+	FinishedAt time.Time
+}
+
+// ReadingDay is the pages read on one calendar day.
+type ReadingDay struct {
+	Day   time.Time
+	Pages int64
+}
+
+type MonthlyBooks struct {
+	Month string `json:"month"`
+	Books int64  `json:"books"`
+}
+
+type DailyPages struct {
+	Date  string `json:"date"`
+	Pages int64  `json:"pages"`
+}
+
+type WeeklyPages struct {
+	WeekStart string `json:"week_start"`
+	Pages     int64  `json:"pages"`
+}
+
+type ReadingStreaks struct {
+	Current    int    `json:"current"`
+	Longest    int    `json:"longest"`
+	LastReadOn string `json:"last_read_on,omitempty"`
+}
+
+type StatsBreakdown struct {
+	Name  string `json:"name"`
+	Books int64  `json:"books"`
+}
+
+type ReadingStats struct {
+	From                string           `json:"from"`
+	To                  string           `json:"to"`
+	BooksFinished       int64            `json:"books_finished"`
+	DidNotFinish        int64            `json:"did_not_finish"`
+	PagesRead           int64            `json:"pages_read"`
+	AverageDaysToFinish *float64         `json:"average_days_to_finish"`
+	BooksPerMonth       []MonthlyBooks   `json:"books_per_month"`
+	PagesPerDay         []DailyPages     `json:"pages_per_day"`
+	PagesPerWeek        []WeeklyPages    `json:"pages_per_week"`
+	Streaks             ReadingStreaks   `json:"streaks"`
+	Genres              []StatsBreakdown `json:"genres"`
+	Authors             []StatsBreakdown `json:"authors"`
+}
@@ -1,9 +1,16 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
 type RegisterRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Name        string `json:"name" binding:"required"`
+	DisplayName string `json:"display_name" binding:"max=50"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,min=6"`
 }
 
 type LoginRequest struct {
@@ -17,3 +24,26 @@ type UserResponse struct {
 	Email string `json:"email"`
 	Token string `json:"token,omitempty"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// UserSettings fields left out of an update keep their current value.
+type UserSettings struct {
+	DisplayName             *string                 `json:"display_name" binding:"omitempty,max=50"`
+	DefaultReviewVisibility models.ReviewVisibility `json:"default_review_visibility" binding:"omitempty,oneof=private followers public"`
+}
+
+// FollowRequest is a follow waiting for the followee's approval.
+type FollowRequest struct {
+	FollowerID   uint      `json:"follower_id"`
+	FollowerName string    `json:"follower_name"`
+	RequestedAt  time.Time `json:"requested_at"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+
+type WorkStats struct {
+	Readers       int64   `json:"readers"`
+	ReviewCount   int64   `json:"review_count"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+type WorkDetail struct {
+	models.Work
+	Stats WorkStats `json:"stats"`
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/catalog"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,10 +36,18 @@ func (h *BookHandler) AddBook(c *gin.Context) {
 		return
 	}
 
-	book, err := h.service.CreateBook(userID, req)
+	book, err := h.service.CreateBook(c.Request.Context(), userID, req)
 	if err != nil {
-
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrMissingBookDetails), errors.Is(err, utils.ErrInvalidISBN):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrDuplicateISBN), errors.Is(err, services.ErrDuplicateTitle),
+			errors.Is(err, repository.ErrDuplicateBook):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add book"})
+		}
 		return
 	}
 
@@ -44,12 +56,25 @@ func (h *BookHandler) AddBook(c *gin.Context) {
 
 func (h *BookHandler) ListBooks(c *gin.Context) {
 	userID := getIDFromContext(c)
-	books, err := h.service.FetchBooks(userID)
+
+	var query dto.BookListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	books, nextCursor, err := h.service.ListBooks(c.Request.Context(), userID, query)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidYearRange) ||
+			errors.Is(err, services.ErrInvalidStatusFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch library"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": books})
+	c.JSON(http.StatusOK, gin.H{"data": books, "next_cursor": nextCursor})
 }
 
 func (h *BookHandler) UpdateBook(c *gin.Context) {
@@ -62,8 +87,9 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	err := h.service.UpdateBook(uint(bookID), userID, req)
+	err := h.service.UpdateBook(c.Request.Context(), uint(bookID), userID, req)
 	if err != nil {
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update book"})
 		return
 	}
@@ -73,8 +99,13 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 	userID := getIDFromContext(c)
 	bookID, _ := strconv.Atoi(c.Param("id"))
-	err := h.service.DeleteBook(uint(bookID), userID)
+	err := h.service.DeleteBook(c.Request.Context(), uint(bookID), userID)
+	if errors.Is(err, services.ErrBookNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete book"})
 		return
 	}
@@ -83,7 +114,7 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 func (h *BookHandler) GetBook(c *gin.Context) {
 	userID := getIDFromContext(c)
 	bookID, _ := strconv.Atoi(c.Param("id"))
-	book, err := h.service.GetSingleBook(uint(bookID), userID)
+	book, err := h.service.GetSingleBook(c.Request.Context(), uint(bookID), userID)
 	if err != nil {
 
 		c.JSON(http.StatusNotFound, gin.H{"error": "book not found"})
@@ -97,8 +128,9 @@ func (h *BookHandler) GetDashboard(c *gin.Context) {
 
 	val, _ := c.Get("user_id")
 	userID := val.(uint)
-	stats, err := h.service.GetDashboardStats(userID)
+	stats, err := h.service.GetDashboardStats(c.Request.Context(), userID)
 	if err != nil {
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate dashboard stats"})
 		return
 	}
@@ -111,16 +143,63 @@ func (h *BookHandler) SearchBooks(c *gin.Context) {
 	query := c.Query("q")
 
 	if query == "" {
-		books, _ := h.service.FetchBooks(userID)
+		books, err := h.service.FetchBooks(c.Request.Context(), userID)
+		if err != nil {
+			c.Error(err)
+			c.JSON(500, gin.H{"error": "Failed to fetch library"})
+			return
+		}
 		c.JSON(200, gin.H{"data": books})
 		return
 	}
 
-	books, err := h.service.SearchMyBooks(userID, query)
+	books, err := h.service.SearchMyBooks(c.Request.Context(), userID, query)
 	if err != nil {
+		c.Error(err)
 		c.JSON(500, gin.H{"error": "Search failed"})
 		return
 	}
 
 	c.JSON(200, gin.H{"data": books})
 }
+
+func (h *BookHandler) Lookup(c *gin.Context) {
+	isbn := c.Query("isbn")
+	title := c.Query("title")
+
+	if isbn == "" && title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "isbn or title is required"})
+		return
+	}
+
+	if isbn != "" {
+		book, err := h.service.LookupISBN(c.Request.Context(), isbn)
+		if err != nil {
+			c.Error(err)
+			c.JSON(lookupErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, book)
+		return
+	}
+
+	books, err := h.service.LookupTitle(c.Request.Context(), title)
+	if err != nil {
+		c.Error(err)
+		c.JSON(lookupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": books})
+}
+
+func lookupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, utils.ErrInvalidISBN):
+		return http.StatusBadRequest
+	case errors.Is(err, catalog.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrCatalogUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+var exportContentTypes = map[string]string{
+	services.ExportFormatJSON:      "application/json",
+	services.ExportFormatCSV:       "text/csv",
+	services.ExportFormatGoodreads: "text/csv",
+}
+
+// exportContents is sent as X-Export-Contents. The CSV formats are one row per
+// book, so reading goals are only in the JSON export.
+var exportContents = map[string]string{
+	services.ExportFormatJSON:      "books, progress, reviews, goals",
+	services.ExportFormatCSV:       "books, progress, reviews",
+	services.ExportFormatGoodreads: "books, progress, reviews",
+}
+
+type ExportHandler struct {
+	service services.ExportService
+}
+
+func NewExportHandler(service services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+func (h *ExportHandler) Export(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	format := c.DefaultQuery("format", services.ExportFormatJSON)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrUnsupportedExportFormat.Error()})
+		return
+	}
+
+	ext := "json"
+	if contentType == "text/csv" {
+		ext = "csv"
+	}
+	filename := fmt.Sprintf("library-%s-%s.%s", format, time.Now().Format("2006-01-02"), ext)
+
+	c.Header("Content-Type", contentType+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("X-Export-Contents", exportContents[format])
+	c.Status(http.StatusOK)
+
+	// headers are already sent, so a failure halfway can only be logged
+	if err := h.service.Export(c.Request.Context(), userID, format, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
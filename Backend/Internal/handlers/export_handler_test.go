@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FakeExportService struct{}
+
+func (FakeExportService) Export(ctx context.Context, userID uint, format string, w io.Writer) error {
+	return nil
+}
+
+func TestExport_SaysWhatIsIncluded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	r.GET("/export", NewExportHandler(FakeExportService{}).Export)
+
+	tests := map[string]string{
+		"json":      "books, progress, reviews, goals",
+		"csv":       "books, progress, reviews",
+		"goodreads": "books, progress, reviews",
+	}
+	for format, want := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export?format="+format, nil))
+
+		if got := w.Header().Get("X-Export-Contents"); got != want {
+			t.Errorf("Expected %s to include %q, got %q", format, want, got)
+		}
+	}
+}
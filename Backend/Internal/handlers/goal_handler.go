@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -25,7 +26,12 @@ func (h *GoalHandler) SetGoal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.service.SetUserGoal(userID, req); err != nil {
+	if err := h.service.SetUserGoal(c.Request.Context(), userID, req); err != nil {
+		if errors.Is(err, services.ErrInvalidGoal) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Error(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set goal"})
 		return
 	}
@@ -39,10 +45,23 @@ func (h *GoalHandler) GetGoalStatus(c *gin.Context) {
 	year, _ := strconv.Atoi(c.Param("year"))
 	month, _ := strconv.Atoi(c.Param("month"))
 
-	status, err := h.service.GetProgress(userID, year, month)
+	status, err := h.service.GetProgress(c.Request.Context(), userID, year, month)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No goal found for this period"})
 		return
 	}
 	c.JSON(http.StatusOK, status)
 }
+
+func (h *GoalHandler) ListGoals(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+
+	goals, err := h.service.ListGoals(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load goals"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": goals})
+}
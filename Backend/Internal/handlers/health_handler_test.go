@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FakePinger struct {
+	Err   error
+	Pings int
+}
+
+func (f *FakePinger) PingContext(ctx context.Context) error {
+	f.Pings++
+	return f.Err
+}
+
+func serveHealth(h *HealthHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestReadiness_DatabaseUp(t *testing.T) {
+	db := &FakePinger{}
+	w := serveHealth(NewHealthHandler(db), "/readyz")
+
+	if w.Code != http.StatusOK || db.Pings != 1 {
+		t.Errorf("Expected 200 after one ping, got %d after %d pings", w.Code, db.Pings)
+	}
+}
+
+func TestReadiness_DatabaseDown(t *testing.T) {
+	w := serveHealth(NewHealthHandler(&FakePinger{Err: errors.New("connection refused")}), "/readyz")
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "connection refused") {
+		t.Errorf("Expected the database error to stay out of the response, got %s", w.Body.String())
+	}
+}
+
+func TestReadiness_Draining(t *testing.T) {
+	db := &FakePinger{}
+	h := NewHealthHandler(db)
+	h.Drain()
+
+	w := serveHealth(h, "/readyz")
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "shutting down") {
+		t.Errorf("Expected 503 while draining, got %d %s", w.Code, w.Body.String())
+	}
+	if db.Pings != 0 {
+		t.Errorf("Expected no ping while draining, got %d", db.Pings)
+	}
+}
+
+func TestLiveness_IgnoresDatabaseAndDraining(t *testing.T) {
+	db := &FakePinger{Err: errors.New("connection refused")}
+	h := NewHealthHandler(db)
+	h.Drain()
+
+	w := serveHealth(h, "/healthz")
+	if w.Code != http.StatusOK || db.Pings != 0 {
+		t.Errorf("Expected 200 without a ping, got %d after %d pings", w.Code, db.Pings)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -17,11 +18,27 @@ func NewProgressHandler(service services.ProgressService) *ProgressHandler {
 	return &ProgressHandler{service: service}
 }
 
+func progressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrProgressAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrNoProgress):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrRereadNotFinished):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidStatus), errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, services.ErrInvalidPage), errors.Is(err, services.ErrInvalidSession),
+		errors.Is(err, services.ErrInvalidDates):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
 func (h *ProgressHandler) GetProgress(c *gin.Context) {
 	val, _ := c.Get("user_id")
 	userID := val.(uint)
 	bookID, _ := strconv.Atoi(c.Param("id"))
-	progress, err := h.service.GetProgress(userID, uint(bookID))
+	progress, err := h.service.GetProgress(c.Request.Context(), userID, uint(bookID))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -40,10 +57,78 @@ func (h *ProgressHandler) UpdateProgress(c *gin.Context) {
 		return
 	}
 
-	err := h.service.UpdateProgress(userID, uint(bookID), req)
+	err := h.service.UpdateProgress(c.Request.Context(), userID, uint(bookID), req)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		c.Error(err)
+		c.JSON(progressErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Progress updated"})
 }
+
+func (h *ProgressHandler) LogSession(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+
+	var req dto.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.LogSession(c.Request.Context(), userID, uint(bookID), req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(progressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *ProgressHandler) GetSessions(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+
+	sessions, err := h.service.GetSessions(c.Request.Context(), userID, uint(bookID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+func (h *ProgressHandler) UpdateDates(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+
+	var req dto.UpdateProgressDatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := h.service.UpdateDates(c.Request.Context(), userID, uint(bookID), req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(progressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
+
+func (h *ProgressHandler) StartReread(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+
+	progress, err := h.service.StartReread(c.Request.Context(), userID, uint(bookID))
+	if err != nil {
+		c.Error(err)
+		c.JSON(progressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, progress)
+}
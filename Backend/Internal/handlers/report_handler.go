@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+var reportContentTypes = map[string]string{
+	services.ReportFormatJSON:     "application/json",
+	services.ReportFormatHTML:     "text/html",
+	services.ReportFormatMarkdown: "text/markdown",
+}
+
+type ReportHandler struct {
+	service services.ReportService
+}
+
+func NewReportHandler(service services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+func (h *ReportHandler) GetYearReport(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	format := c.DefaultQuery("format", services.ReportFormatJSON)
+	contentType, ok := reportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrUnsupportedReportFormat.Error()})
+		return
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidReportYear.Error()})
+		return
+	}
+
+	report, err := h.service.GetYearReport(c.Request.Context(), userID, year)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportYear) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.Render(report, format, &buf); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report"})
+		return
+	}
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", buf.Bytes())
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -28,7 +29,7 @@ func (h *ReviewHandler) AddReview(c *gin.Context) {
 		return
 	}
 
-	err := h.service.AddReview(userID, uint(bookID), req)
+	err := h.service.AddReview(c.Request.Context(), userID, uint(bookID), req)
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
@@ -41,11 +42,117 @@ func (h *ReviewHandler) GetReviews(c *gin.Context) {
 	val, _ := c.Get("user_id")
 	userID := val.(uint)
 	bookID, _ := strconv.Atoi(c.Param("id"))
-	reviews, err := h.service.GetBookReviews(userID, uint(bookID))
+
+	var query dto.ReviewFeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed, err := h.service.GetBookReviews(c.Request.Context(), userID, uint(bookID), query)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.JSON(http.StatusOK, feed)
+}
+
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	reviewID, _ := strconv.Atoi(c.Param("reviewId"))
+
+	var req dto.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.service.UpdateReview(c.Request.Context(), userID, uint(bookID), uint(reviewID), req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	reviewID, _ := strconv.Atoi(c.Param("reviewId"))
+
+	if err := h.service.DeleteReview(c.Request.Context(), userID, uint(bookID), uint(reviewID)); err != nil {
+		c.Error(err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	bookID, _ := strconv.Atoi(c.Param("id"))
+	reviewID, _ := strconv.Atoi(c.Param("reviewId"))
+
+	history, err := h.service.GetReviewHistory(c.Request.Context(), userID, uint(bookID), uint(reviewID))
+	if err != nil {
+		c.Error(err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+func (h *ReviewHandler) GetMyReviews(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+
+	reviews, err := h.service.GetMyReviews(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load your reviews"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"data": reviews})
 }
+
+func (h *ReviewHandler) MarkHelpful(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	reviewID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.MarkHelpful(c.Request.Context(), userID, uint(reviewID)); err != nil {
+		c.Error(err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Marked as helpful"})
+}
+
+func (h *ReviewHandler) UnmarkHelpful(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	reviewID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.UnmarkHelpful(c.Request.Context(), userID, uint(reviewID)); err != nil {
+		c.Error(err)
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Vote removed"})
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrOwnReviewVote), errors.Is(err, services.ErrReviewAccessDenied):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ShelfHandler struct {
+	service services.ShelfService
+}
+
+func NewShelfHandler(service services.ShelfService) *ShelfHandler {
+	return &ShelfHandler{service: service}
+}
+
+func shelfErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrShelfNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrShelfExists):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidShelf), errors.Is(err, services.ErrBooksNotOwned), errors.Is(err, services.ErrInvalidTagName):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *ShelfHandler) CreateShelf(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	var req dto.ShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shelf, err := h.service.CreateShelf(c.Request.Context(), userID, req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, shelf)
+}
+
+func (h *ShelfHandler) ListShelves(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	shelves, err := h.service.ListShelves(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load shelves"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": shelves})
+}
+
+func (h *ShelfHandler) GetShelf(c *gin.Context) {
+	userID := getIDFromContext(c)
+	shelfID, _ := strconv.Atoi(c.Param("id"))
+
+	shelf, err := h.service.GetShelf(c.Request.Context(), userID, uint(shelfID))
+	if err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, shelf)
+}
+
+func (h *ShelfHandler) UpdateShelf(c *gin.Context) {
+	userID := getIDFromContext(c)
+	shelfID, _ := strconv.Atoi(c.Param("id"))
+
+	var req dto.ShelfRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shelf, err := h.service.UpdateShelf(c.Request.Context(), userID, uint(shelfID), req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, shelf)
+}
+
+func (h *ShelfHandler) DeleteShelf(c *gin.Context) {
+	userID := getIDFromContext(c)
+	shelfID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.DeleteShelf(c.Request.Context(), userID, uint(shelfID)); err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shelf deleted"})
+}
+
+func (h *ShelfHandler) AddBooks(c *gin.Context) {
+	userID := getIDFromContext(c)
+	shelfID, _ := strconv.Atoi(c.Param("id"))
+
+	var req dto.ShelfBooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.AddBooks(c.Request.Context(), userID, uint(shelfID), req.BookIDs); err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Books added to shelf"})
+}
+
+func (h *ShelfHandler) RemoveBooks(c *gin.Context) {
+	userID := getIDFromContext(c)
+	shelfID, _ := strconv.Atoi(c.Param("id"))
+
+	var req dto.ShelfBooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.RemoveBooks(c.Request.Context(), userID, uint(shelfID), req.BookIDs); err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Books removed from shelf"})
+}
+
+func (h *ShelfHandler) ListTags(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	tags, err := h.service.ListTags(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load tags"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+func (h *ShelfHandler) TagBooks(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	var req dto.TagBooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.TagBooks(c.Request.Context(), userID, req); err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Books tagged"})
+}
+
+func (h *ShelfHandler) UntagBooks(c *gin.Context) {
+	userID := getIDFromContext(c)
+
+	var req dto.TagBooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UntagBooks(c.Request.Context(), userID, req); err != nil {
+		c.Error(err)
+		c.JSON(shelfErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tags removed"})
+}
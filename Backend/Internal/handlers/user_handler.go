@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
@@ -24,7 +26,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.Register(req)
+	user, err := h.service.Register(c.Request.Context(), req)
 	if err != nil {
 
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -44,13 +46,150 @@ func (h *UserHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	token, err := h.service.Login(req)
-	if err != nil {
+	tokens, err := h.service.Login(c.Request.Context(), req)
+	if errors.Is(err, services.ErrInvalidCredentials) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
+		"message":       "Login successful",
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 	})
 }
+
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	sessionID := c.GetString("session_id")
+	if err := h.service.Logout(c.Request.Context(), sessionID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+func (h *UserHandler) GetSettings(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *UserHandler) UpdateSettings(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+
+	var req dto.UserSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), userID, req)
+	if err != nil {
+		c.Error(err)
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *UserHandler) Follow(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	targetID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.Follow(c.Request.Context(), userID, uint(targetID)); err != nil {
+		c.Error(err)
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Follow request sent"})
+}
+
+func (h *UserHandler) Unfollow(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	targetID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.Unfollow(c.Request.Context(), userID, uint(targetID)); err != nil {
+		c.Error(err)
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed user"})
+}
+
+func (h *UserHandler) GetFollowRequests(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+
+	requests, err := h.service.GetFollowRequests(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load follow requests"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": requests})
+}
+
+func (h *UserHandler) ApproveFollower(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	followerID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.ApproveFollower(c.Request.Context(), userID, uint(followerID)); err != nil {
+		c.Error(err)
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Follower approved"})
+}
+
+func (h *UserHandler) RemoveFollower(c *gin.Context) {
+	val, _ := c.Get("user_id")
+	userID := val.(uint)
+	followerID, _ := strconv.Atoi(c.Param("id"))
+
+	if err := h.service.RemoveFollower(c.Request.Context(), userID, uint(followerID)); err != nil {
+		c.Error(err)
+		c.JSON(followErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Follower removed"})
+}
+
+func followErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrNoFollowRequest):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrFollowSelf):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
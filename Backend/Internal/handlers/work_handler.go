@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkHandler struct {
+	service services.WorkService
+}
+
+func NewWorkHandler(service services.WorkService) *WorkHandler {
+	return &WorkHandler{service: service}
+}
+
+func (h *WorkHandler) GetWork(c *gin.Context) {
+	workID, _ := strconv.Atoi(c.Param("id"))
+
+	work, err := h.service.GetWork(c.Request.Context(), uint(workID))
+	if err != nil {
+		if errors.Is(err, services.ErrWorkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load work"})
+		return
+	}
+	c.JSON(http.StatusOK, work)
+}
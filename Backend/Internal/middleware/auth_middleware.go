@@ -1,16 +1,19 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+var errSessionInactive = errors.New("session is not active")
+
+func AuthMiddleware(tokenRepo repository.TokenRepository, secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		authHeader := c.GetHeader("Authorization")
@@ -29,16 +32,12 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-
-			secret := os.Getenv("JWT_SECRET")
 			return []byte(secret), nil
 		})
 
 		if err != nil {
-			fmt.Println("--- JWT DEBUG ERROR ---")
-			fmt.Printf("Message: %v\n", err)
-			fmt.Printf("Secret Key used by Middleware: '%s'\n", os.Getenv("JWT_SECRET"))
-			fmt.Println("-----------------------")
+			// the request log records why the token was rejected, never the token or secret
+			c.Error(err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
@@ -46,8 +45,22 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 			userID := uint(claims["user_id"].(float64))
+			sessionID, _ := claims["sid"].(string)
+
+			active, err := tokenRepo.IsSessionActive(c.Request.Context(), sessionID)
+			if sessionID == "" || err != nil || !active {
+				if err == nil {
+					err = errSessionInactive
+				}
+				c.Error(err)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked, please log in again"})
+				c.Abort()
+				return
+			}
 
 			c.Set("user_id", userID)
+			c.Set("session_id", sessionID)
+			c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "user_id", userID))
 		} else {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			c.Abort()
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records every request by its route pattern, so /api/books/1 and
+// /api/books/2 share one series.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		method, route := c.Request.Method, c.FullPath()
+		if route == "" {
+			// any path or method can reach here, so keep them out of the labels
+			method, route = "OTHER", "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
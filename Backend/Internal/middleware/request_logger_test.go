@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// logLines decodes the JSON lines written to buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if raw == "" {
+			continue
+		}
+		var line map[string]any
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("Expected a JSON log line, got %q", raw)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestLogger_LogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(logging.New(&buf, slog.LevelInfo)))
+	r.GET("/api/books/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("inside handler")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/books/7", nil))
+
+	requestID := w.Header().Get(RequestIDHeader)
+	if len(requestID) != 32 {
+		t.Fatalf("Expected a generated request ID, got %q", requestID)
+	}
+	lines := logLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected a handler line and a request line, got %v", lines)
+	}
+	if lines[0]["request_id"] != requestID {
+		t.Errorf("Expected the handler's log to carry the request ID, got %v", lines[0])
+	}
+	req := lines[1]
+	if req["msg"] != "request" || req["level"] != "INFO" || req["request_id"] != requestID {
+		t.Errorf("Unexpected request line %v", req)
+	}
+	if req["route"] != "/api/books/:id" || req["path"] != "/api/books/7" || req["status"] != float64(200) {
+		t.Errorf("Expected route, path and status in %v", req)
+	}
+}
+
+func TestRequestLogger_ReusesValidRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(logging.New(&buf, slog.LevelInfo)))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "edge-4f2a:1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "edge-4f2a:1" {
+		t.Errorf("Expected the caller's request ID back, got %q", got)
+	}
+	if lines := logLines(t, &buf); lines[0]["request_id"] != "edge-4f2a:1" {
+		t.Errorf("Expected the caller's request ID in the log, got %v", lines[0])
+	}
+}
+
+func TestRequestLogger_ReplacesInvalidRequestID(t *testing.T) {
+	for _, id := range []string{"has space", "line\nbreak", `quote"d`, strings.Repeat("a", 129)} {
+		var buf bytes.Buffer
+		r := gin.New()
+		r.Use(RequestLogger(logging.New(&buf, slog.LevelInfo)))
+		r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get(RequestIDHeader); got == id || len(got) != 32 {
+			t.Errorf("Expected %q to be replaced with a generated ID, got %q", id, got)
+		}
+	}
+}
+
+func TestRequestLogger_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		handler gin.HandlerFunc
+		level   string
+		err     string
+	}{
+		{"success", "/ping", func(c *gin.Context) { c.Status(http.StatusOK) }, "INFO", ""},
+		{"client error", "/ping", func(c *gin.Context) { c.Status(http.StatusNotFound) }, "WARN", ""},
+		{"server error", "/ping", func(c *gin.Context) {
+			c.Error(errors.New("connection refused"))
+			c.Status(http.StatusInternalServerError)
+		}, "ERROR", "connection refused"},
+		{"error after a success status", "/ping", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+			c.Error(errors.New("stream broke"))
+		}, "ERROR", "stream broke"},
+		{"quiet route", "/healthz", func(c *gin.Context) { c.Status(http.StatusOK) }, "DEBUG", ""},
+		{"failing quiet route", "/healthz", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) }, "ERROR", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := gin.New()
+			r.Use(RequestLogger(logging.New(&buf, slog.LevelDebug), "/healthz"))
+			r.GET(tt.path, tt.handler)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			lines := logLines(t, &buf)
+			if len(lines) != 1 {
+				t.Fatalf("Expected one request line, got %v", lines)
+			}
+			if lines[0]["level"] != tt.level {
+				t.Errorf("Expected level %s, got %v", tt.level, lines[0]["level"])
+			}
+			if tt.err != "" && lines[0]["error"] != tt.err {
+				t.Errorf("Expected error %q, got %v", tt.err, lines[0]["error"])
+			}
+		})
+	}
+}
+
+func TestRecovery_LogsPanicAs500(t *testing.T) {
+	// keep the stack trace gin prints out of the test output
+	defer func(w io.Writer) { gin.DefaultErrorWriter = w }(gin.DefaultErrorWriter)
+	gin.DefaultErrorWriter = io.Discard
+
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(logging.New(&buf, slog.LevelInfo)), Recovery())
+	r.GET("/boom", func(c *gin.Context) { panic("nil map") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.Code)
+	}
+	lines := logLines(t, &buf)
+	if len(lines) != 1 || lines[0]["level"] != "ERROR" || lines[0]["error"] != "panic: nil map" {
+		t.Errorf("Expected the panic in the request line, got %v", lines)
+	}
+}
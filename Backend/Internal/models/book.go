@@ -2,9 +2,11 @@ package models
 
 import "time"
 
+// Book is one user's library entry. Shared bibliographic data lives on the
+// linked Work/Edition; the fields here are the user's own copy of it.
 type Book struct {
 	ID     uint `json:"id" gorm:"primaryKey"`
-	UserID uint `json:"user_id" gorm:"not null"`
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex:idx_user_title_author"`
 
 	User   User   `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 	Title  string `json:"title" gorm:"not null;uniqueIndex:idx_user_title_author"`
@@ -12,10 +14,18 @@ type Book struct {
 
 	ISBN string `json:"isbn"`
 
-	Genre           string           `json:"genre"`
-	PublicationYear int              `json:"publication_year"`
-	TotalPages      int              `json:"total_pages"`
-	Progress        *ReadingProgress `json:"progress" gorm:"foreignKey:BookID"`
-	CreatedAt       time.Time        `json:"created_at"`
-	UpdatedAt       time.Time        `json:"updated_at"`
+	WorkID    *uint    `json:"work_id" gorm:"index"`
+	Work      *Work    `json:"-" gorm:"foreignKey:WorkID;constraint:OnDelete:SET NULL;"`
+	EditionID *uint    `json:"edition_id" gorm:"index"`
+	Edition   *Edition `json:"-" gorm:"foreignKey:EditionID;constraint:OnDelete:SET NULL;"`
+
+	Genre           string            `json:"genre"`
+	PublicationYear int               `json:"publication_year"`
+	TotalPages      int               `json:"total_pages"`
+	Progress        *ReadingProgress  `json:"progress" gorm:"foreignKey:BookID"`
+	ReadHistory     []ReadingProgress `json:"-" gorm:"foreignKey:BookID"`
+	Shelves         []Shelf           `json:"-" gorm:"many2many:book_shelves;"`
+	Tags            []Tag             `json:"tags" gorm:"many2many:book_tags;"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
 }
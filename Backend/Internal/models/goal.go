@@ -2,13 +2,31 @@ package models
 
 import "time"
 
+const (
+	GoalTypeMonthly = "monthly"
+	GoalTypeYearly  = "yearly"
+	GoalTypeWeekly  = "weekly"
+	GoalTypeCustom  = "custom"
+
+	GoalMetricBooks = "books"
+	GoalMetricPages = "pages"
+)
+
+// ReadingGoal covers a period from StartDate to EndDate (both inclusive).
+// Year/Month/Week are kept for the period-based lookups of calendar goals.
 type ReadingGoal struct {
 	ID uint `json:"id" gorm:"primaryKey"`
 
-	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_year_month"`
-	Year        int       `json:"year" gorm:"not null;uniqueIndex:idx_user_year_month"`
-	Month       int       `json:"month" gorm:"not null;uniqueIndex:idx_user_year_month"`
-	TargetBooks int       `json:"target_books" gorm:"not null"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	UserID      uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_goal_period"`
+	Type        string     `json:"type" gorm:"not null;default:'monthly';uniqueIndex:idx_goal_period"`
+	Metric      string     `json:"metric" gorm:"not null;default:'books';uniqueIndex:idx_goal_period"`
+	Year        int        `json:"year" gorm:"not null"`
+	Month       int        `json:"month" gorm:"not null"`
+	Week        int        `json:"week" gorm:"not null;default:0"`
+	StartDate   *time.Time `json:"start_date" gorm:"type:date;uniqueIndex:idx_goal_period"`
+	EndDate     *time.Time `json:"end_date" gorm:"type:date;uniqueIndex:idx_goal_period"`
+	TargetBooks int        `json:"target_books" gorm:"not null"`
+	TargetPages int        `json:"target_pages"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -2,11 +2,36 @@ package models
 
 import "time"
 
+type ReadingStatus string
+
+const (
+	StatusWantToRead   ReadingStatus = "Want to Read"
+	StatusReading      ReadingStatus = "Currently Reading"
+	StatusPaused       ReadingStatus = "Paused"
+	StatusFinished     ReadingStatus = "Finished"
+	StatusDidNotFinish ReadingStatus = "Did Not Finish"
+)
+
+func (s ReadingStatus) Valid() bool {
+	switch s {
+	case StatusWantToRead, StatusReading, StatusPaused, StatusFinished, StatusDidNotFinish:
+		return true
+	}
+	return false
+}
+
+// ReadingProgress is one read-through of a book. Re-reading a book starts a
+// new row with the next ReadNumber; only the latest one is current.
 type ReadingProgress struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	BookID      uint      `json:"book_id" gorm:"not null"`
-	Book        Book      `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE;"`
-	CurrentPage int       `json:"current_page" gorm:"default:0"`
-	Status      string    `json:"status" gorm:"default:'Want to Read'"`
-	LastUpdated time.Time `json:"last_updated" gorm:"autoUpdateTime"`
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	BookID      uint          `json:"book_id" gorm:"not null;uniqueIndex:idx_book_read_number"`
+	Book        Book          `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE;"`
+	ReadNumber  int           `json:"read_number" gorm:"not null;default:1;uniqueIndex:idx_book_read_number"`
+	IsCurrent   bool          `json:"is_current" gorm:"not null;default:true;index"`
+	CurrentPage int           `json:"current_page" gorm:"default:0"`
+	Status      ReadingStatus `json:"status" gorm:"default:'Want to Read'"`
+	StartedAt   *time.Time    `json:"started_at"`
+	FinishedAt  *time.Time    `json:"finished_at" gorm:"index"`
+	LastUpdated time.Time     `json:"last_updated" gorm:"autoUpdateTime"`
+	Review      *Review       `json:"-" gorm:"foreignKey:ReadingProgressID;constraint:OnDelete:SET NULL;"`
 }
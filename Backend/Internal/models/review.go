@@ -2,12 +2,59 @@ package models
 
 import "time"
 
+// ReviewVisibility controls who besides the author can see a review.
+type ReviewVisibility string
+
+const (
+	VisibilityPrivate   ReviewVisibility = "private"
+	VisibilityFollowers ReviewVisibility = "followers"
+	VisibilityPublic    ReviewVisibility = "public"
+)
+
+func (v ReviewVisibility) Valid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityFollowers, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
 type Review struct {
 	ID     uint `json:"id" gorm:"primaryKey"`
 	BookID uint `json:"book_id" gorm:"not null"`
 
-	Book      Book      `json:"book" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE;"`
-	Rating    int       `json:"rating"`
-	Comment   string    `json:"comment"`
+	Book Book `json:"book" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE;"`
+
+	UserID uint `json:"user_id" gorm:"not null;index"`
+	User   User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+
+	ReadingProgressID *uint            `json:"read_through_id" gorm:"index"`
+	Rating            int              `json:"rating"`
+	Comment           string           `json:"comment"`
+	Visibility        ReviewVisibility `json:"visibility" gorm:"not null;default:'public';index"`
+	IsSpoiler         bool             `json:"is_spoiler" gorm:"not null;default:false"`
+	CreatedAt         time.Time        `json:"created_at"`
+	EditedAt          *time.Time       `json:"edited_at"`
+}
+
+// ReviewRevision keeps what a review said before each edit.
+type ReviewRevision struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	ReviewID uint   `json:"review_id" gorm:"not null;index"`
+	Review   Review `json:"-" gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE;"`
+	Rating   int    `json:"rating"`
+	Comment  string `json:"comment"`
+	// WrittenAt is when this version was first posted or last edited
+	WrittenAt time.Time `json:"written_at"`
+	CreatedAt time.Time `json:"replaced_at"`
+}
+
+// ReviewVote is one reader marking someone else's review as helpful.
+type ReviewVote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ReviewID  uint      `json:"review_id" gorm:"not null;uniqueIndex:idx_review_voter"`
+	Review    Review    `json:"-" gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE;"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_review_voter"`
+	User      User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 	CreatedAt time.Time `json:"created_at"`
 }
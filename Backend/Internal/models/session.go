@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type ReadingSession struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	BookID          uint      `json:"book_id" gorm:"not null;index"`
+	Book            Book      `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE;"`
+	StartPage       int       `json:"start_page" gorm:"not null"`
+	EndPage         int       `json:"end_page" gorm:"not null"`
+	StartedAt       time.Time `json:"started_at" gorm:"not null"`
+	EndedAt         time.Time `json:"ended_at" gorm:"not null"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Note            string    `json:"note"`
+	CreatedAt       time.Time `json:"created_at"`
+}
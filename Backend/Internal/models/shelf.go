@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Shelf is a user-defined list of books, e.g. "book club" or "to buy".
+type Shelf struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_shelf_name"`
+	User        User      `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_user_shelf_name"`
+	Description string    `json:"description"`
+	Books       []Book    `json:"books,omitempty" gorm:"many2many:book_shelves;"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Tag is a free-form label; names are stored lower-cased.
+type Tag struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	UserID uint   `json:"-" gorm:"not null;uniqueIndex:idx_user_tag_name"`
+	User   User   `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Name   string `json:"name" gorm:"not null;uniqueIndex:idx_user_tag_name"`
+}
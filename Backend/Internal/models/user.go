@@ -3,10 +3,26 @@ package models
 import "time"
 
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"not null"`
-	Email     string    `json:"email" gorm:"unique;not null"`
-	Password  string    `json:"-" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	// DisplayName is what other readers see; empty falls back to the first name
+	DisplayName string `json:"display_name"`
+	// DefaultReviewVisibility applies to new reviews that do not pick one
+	DefaultReviewVisibility ReviewVisibility `json:"default_review_visibility" gorm:"not null;default:'public'"`
+	Email                   string           `json:"email" gorm:"unique;not null"`
+	Password                string           `json:"-" gorm:"not null"`
+	CreatedAt               time.Time        `json:"created_at"`
+	UpdatedAt               time.Time        `json:"updated_at"`
+}
+
+// Follow lets FollowerID see FolloweeID's followers-only reviews once
+// FolloweeID has approved it.
+type Follow struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	FollowerID uint      `json:"follower_id" gorm:"not null;uniqueIndex:idx_follow_pair"`
+	Follower   User      `json:"-" gorm:"foreignKey:FollowerID;constraint:OnDelete:CASCADE;"`
+	FolloweeID uint      `json:"followee_id" gorm:"not null;uniqueIndex:idx_follow_pair;index"`
+	Followee   User      `json:"-" gorm:"foreignKey:FolloweeID;constraint:OnDelete:CASCADE;"`
+	Approved   bool      `json:"approved" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Work is a book in the shared catalog, independent of who owns it or which
+// edition they read. Library entries (Book) point at a work and, when their
+// ISBN is known, at a specific edition of it.
+type Work struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Key       string    `json:"-" gorm:"not null;uniqueIndex"`
+	Title     string    `json:"title" gorm:"not null"`
+	Author    string    `json:"author" gorm:"not null"`
+	Genre     string    `json:"genre"`
+	Editions  []Edition `json:"editions,omitempty" gorm:"foreignKey:WorkID"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Edition struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	WorkID          uint      `json:"work_id" gorm:"not null;index"`
+	Work            *Work     `json:"-" gorm:"foreignKey:WorkID;constraint:OnDelete:CASCADE;"`
+	ISBN            string    `json:"isbn" gorm:"not null;uniqueIndex"`
+	PublicationYear int       `json:"publication_year"`
+	TotalPages      int       `json:"total_pages"`
+	CreatedAt       time.Time `json:"created_at"`
+}
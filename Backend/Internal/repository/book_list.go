@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// sortColumn is the SQL expression a list can be ordered by, plus the type its
+// cursor value is cast back to when resuming a page.
+type sortColumn struct {
+	expr        string
+	castType    string
+	defaultDesc bool
+}
+
+var bookSortColumns = map[string]sortColumn{
+	"title":        {expr: "LOWER(books.title)", castType: "text"},
+	"author":       {expr: "LOWER(books.author)", castType: "text"},
+	"created_at":   {expr: "books.created_at", castType: "timestamptz", defaultDesc: true},
+	"last_updated": {expr: "COALESCE(reading_progresses.last_updated, books.updated_at)", castType: "timestamptz", defaultDesc: true},
+	"rating":       {expr: "COALESCE((SELECT MAX(reviews.rating) FROM reviews WHERE reviews.book_id = books.id), 0)", castType: "integer", defaultDesc: true},
+}
+
+type bookCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func encodeBookCursor(c bookCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeBookCursor(s string) (*bookCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c bookCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type bookListRow struct {
+	ID        uint
+	SortValue string
+}
+
+func (r *bookRepository) ListBooks(ctx context.Context, userID uint, q dto.BookListQuery) ([]models.Book, string, error) {
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = "created_at"
+	}
+	col, ok := bookSortColumns[sortName]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported sort field %q", sortName)
+	}
+	desc := col.defaultDesc
+	if q.Order != "" {
+		desc = q.Order == "desc"
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	query := r.db.WithContext(ctx).Table("books").
+		Select(fmt.Sprintf("books.id AS id, CAST(%s AS TEXT) AS sort_value", col.expr)).
+		Joins("LEFT JOIN reading_progresses ON reading_progresses.book_id = books.id AND reading_progresses.is_current").
+		Where("books.user_id = ?", userID)
+
+	if q.Genre != "" {
+		query = query.Where("LOWER(books.genre) = LOWER(?)", q.Genre)
+	}
+	if q.Author != "" {
+		query = query.Where("books.author ILIKE ?", "%"+q.Author+"%")
+	}
+	if q.Status != "" {
+		query = query.Where("COALESCE(reading_progresses.status, 'Want to Read') = ?", q.Status)
+	}
+	if q.Shelf > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM book_shelves JOIN shelves ON shelves.id = book_shelves.shelf_id WHERE book_shelves.book_id = books.id AND shelves.id = ? AND shelves.user_id = ?)", q.Shelf, userID)
+	}
+	if q.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM book_tags JOIN tags ON tags.id = book_tags.tag_id WHERE book_tags.book_id = books.id AND tags.name = LOWER(?))", strings.TrimSpace(q.Tag))
+	}
+	if q.YearFrom > 0 {
+		query = query.Where("books.publication_year >= ?", q.YearFrom)
+	}
+	if q.YearTo > 0 {
+		query = query.Where("books.publication_year <= ?", q.YearTo)
+	}
+
+	if q.Cursor != "" {
+		cursor, err := decodeBookCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		// a cursor only makes sense for the ordering it was issued for
+		if cursor.Sort != sortName || cursor.Desc != desc {
+			return nil, "", ErrInvalidCursor
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		query = query.Where(
+			fmt.Sprintf("(%s, books.id) %s (CAST(? AS %s), ?)", col.expr, op, col.castType),
+			cursor.Value, cursor.ID,
+		)
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	query = query.Order(fmt.Sprintf("%s %s, books.id %s", col.expr, direction, direction)).Limit(limit + 1)
+
+	var rows []bookListRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor = encodeBookCursor(bookCursor{Sort: sortName, Desc: desc, Value: last.SortValue, ID: last.ID})
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	books, err := r.loadBooksInOrder(ctx, ids)
+	if err != nil {
+		return nil, "", err
+	}
+	return books, nextCursor, nil
+}
+
+// loadBooksInOrder fetches full books for ids picked by a previous query,
+// keeping that query's ordering since IN () loses it.
+func (r *bookRepository) loadBooksInOrder(ctx context.Context, ids []uint) ([]models.Book, error) {
+	if len(ids) == 0 {
+		return []models.Book{}, nil
+	}
+
+	var books []models.Book
+	if err := r.db.WithContext(ctx).Preload("Progress", currentReadOnly).Preload("Tags").Where("id IN ?", ids).Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Book, len(books))
+	for _, b := range books {
+		byID[b.ID] = b
+	}
+	ordered := make([]models.Book, 0, len(ids))
+	for _, id := range ids {
+		if b, ok := byID[id]; ok {
+			ordered = append(ordered, b)
+		}
+	}
+	return ordered, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
@@ -11,16 +12,26 @@ import (
 )
 
 type BookRepository interface {
-	CreateBook(book *models.Book) error
-	GetBooksByUserID(userID uint) ([]models.Book, error)
-	GetBookByID(bookID uint, userID uint) (*models.Book, error)
-	UpdateBook(bookID uint, userID uint, book *models.Book) error
-	DeleteBook(id uint, userID uint) error
-	GetDashboardStats(userID uint) (dto.DashboardStats, error)
-	SearchBooks(userID uint, query string) ([]models.Book, error)
-	FindDuplicate(userID uint, title string, author string, isbn string) (*models.Book, error)
+	CreateBook(ctx context.Context, book *models.Book) error
+	GetBooksByUserID(ctx context.Context, userID uint) ([]models.Book, error)
+	ListBooks(ctx context.Context, userID uint, query dto.BookListQuery) ([]models.Book, string, error)
+	GetBookByID(ctx context.Context, bookID uint, userID uint) (*models.Book, error)
+	GetBookWithHistory(ctx context.Context, bookID uint, userID uint) (*models.Book, error)
+	GetReadHistories(ctx context.Context, userID uint, bookIDs []uint) ([]models.ReadingProgress, error)
+	UpdateBook(ctx context.Context, bookID uint, userID uint, book *models.Book) error
+	DeleteBook(ctx context.Context, id uint, userID uint) error
+	GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error)
+	SearchBooks(ctx context.Context, userID uint, query string) ([]models.Book, error)
+	FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error)
 }
 
+// currentReadOnly limits a Progress preload to the book's current read-through.
+func currentReadOnly(db *gorm.DB) *gorm.DB {
+	return db.Where("is_current")
+}
+
+var ErrDuplicateBook = errors.New("this book is already in your library")
+
 type bookRepository struct {
 	db *gorm.DB
 }
@@ -29,20 +40,20 @@ func NewBookRepository(db *gorm.DB) BookRepository {
 	return &bookRepository{db: db}
 }
 
-func (r *bookRepository) CreateBook(book *models.Book) error {
-	err := r.db.Create(book).Error
+func (r *bookRepository) CreateBook(ctx context.Context, book *models.Book) error {
+	err := r.db.WithContext(ctx).Create(book).Error
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") || strings.Contains(err.Error(), "unique") {
-			return errors.New("this book is already in your library")
+			return ErrDuplicateBook
 		}
 		return err
 	}
 	return nil
 }
 
-func (r *bookRepository) FindDuplicate(userID uint, title string, author string, isbn string) (*models.Book, error) {
+func (r *bookRepository) FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error) {
 	var book models.Book
-	err := r.db.Where("user_id = ? AND ((LOWER(title) = LOWER(?) AND LOWER(author) = LOWER(?)) OR (isbn != '' AND isbn = ?))",
+	err := r.db.WithContext(ctx).Where("user_id = ? AND ((LOWER(title) = LOWER(?) AND LOWER(author) = LOWER(?)) OR (isbn != '' AND isbn = ?))",
 		userID, title, author, isbn).First(&book).Error
 
 	if err != nil {
@@ -51,88 +62,144 @@ func (r *bookRepository) FindDuplicate(userID uint, title string, author string,
 	return &book, nil
 }
 
-func (r *bookRepository) GetBooksByUserID(userID uint) ([]models.Book, error) {
+func (r *bookRepository) GetBooksByUserID(ctx context.Context, userID uint) ([]models.Book, error) {
 	var books []models.Book
-	err := r.db.Preload("Progress").Where("user_id = ?", userID).Find(&books).Error
+	err := r.db.WithContext(ctx).Preload("Progress", currentReadOnly).Where("user_id = ?", userID).Find(&books).Error
 	return books, err
 }
 
-func (r *bookRepository) GetBookByID(id uint, userID uint) (*models.Book, error) {
+func (r *bookRepository) GetBookByID(ctx context.Context, id uint, userID uint) (*models.Book, error) {
 	var book models.Book
-	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&book).Error
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&book).Error
 	return &book, err
 }
 
-func (r *bookRepository) UpdateBook(bookID uint, userID uint, book *models.Book) error {
-	return r.db.Model(&models.Book{}).Where("id = ? AND user_id = ?", bookID, userID).Updates(book).Error
+// GetBookWithHistory loads the book with every read-through and the review
+// written for each one.
+func (r *bookRepository) GetBookWithHistory(ctx context.Context, id uint, userID uint) (*models.Book, error) {
+	var book models.Book
+	err := r.db.WithContext(ctx).
+		Preload("Progress", currentReadOnly).
+		Preload("ReadHistory", func(db *gorm.DB) *gorm.DB { return db.Order("read_number asc") }).
+		Preload("ReadHistory.Review").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&book).Error
+	return &book, err
 }
 
-func (r *bookRepository) DeleteBook(id uint, userID uint) error {
-
-	r.db.Where("book_id = ?", id).Delete(&models.ReadingProgress{})
-	r.db.Where("book_id = ?", id).Delete(&models.Review{})
-
-	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Book{})
+// GetReadHistories loads every read-through of the given books in one query,
+// ordered by book and then read number.
+func (r *bookRepository) GetReadHistories(ctx context.Context, userID uint, bookIDs []uint) ([]models.ReadingProgress, error) {
+	var reads []models.ReadingProgress
+	err := r.db.WithContext(ctx).
+		Joins("JOIN books ON books.id = reading_progresses.book_id").
+		Where("books.user_id = ? AND reading_progresses.book_id IN ?", userID, bookIDs).
+		Order("reading_progresses.book_id, reading_progresses.read_number").
+		Find(&reads).Error
+	return reads, err
+}
 
-	if result.Error != nil {
-		return result.Error
-	}
+func (r *bookRepository) UpdateBook(ctx context.Context, bookID uint, userID uint, book *models.Book) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Book{}).Where("id = ? AND user_id = ?", bookID, userID).Updates(book).Error; err != nil {
+			return err
+		}
+		// a relinked book takes the edition of its new work, which may be none;
+		// Updates skips nil fields so it is written separately
+		if book.WorkID == nil {
+			return nil
+		}
+		return tx.Model(&models.Book{}).Where("id = ? AND user_id = ?", bookID, userID).Update("edition_id", book.EditionID).Error
+	})
+}
 
-	if result.RowsAffected == 0 {
-		return errors.New("book not found or you don't have permission to delete it")
-	}
+// DeleteBook removes the book and everything hanging off it. The book is
+// looked up first so nothing is touched unless userID owns it.
+func (r *bookRepository) DeleteBook(ctx context.Context, id uint, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var book models.Book
+		if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&book).Error; err != nil {
+			return err
+		}
 
-	return nil
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.Review{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.ReadingProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", book.ID).Delete(&models.ReadingSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM book_shelves WHERE book_id = ?", book.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM book_tags WHERE book_id = ?", book.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&book).Error
+	})
 }
 
-func (r *bookRepository) GetDashboardStats(userID uint) (dto.DashboardStats, error) {
+func (r *bookRepository) GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error) {
 	var stats dto.DashboardStats
 	now := time.Now()
 	currentYear := now.Year()
 	currentMonth := int(now.Month())
 
-	r.db.Model(&models.Book{}).Where("user_id = ?", userID).Count(&stats.TotalBooks)
+	r.db.WithContext(ctx).Model(&models.Book{}).Where("user_id = ?", userID).Count(&stats.TotalBooks)
 
 	// currently reading count
-	r.db.Table("reading_progresses").
+	r.db.WithContext(ctx).Table("reading_progresses").
 		Joins("JOIN books ON books.id = reading_progresses.book_id").
-		Where("books.user_id = ? AND reading_progresses.status = ?", userID, "Currently Reading").
+		Where("books.user_id = ? AND reading_progresses.is_current AND reading_progresses.status = ?", userID, models.StatusReading).
 		Count(&stats.CurrentlyReading)
 
+	r.db.WithContext(ctx).Table("reading_progresses").
+		Joins("JOIN books ON books.id = reading_progresses.book_id").
+		Where("books.user_id = ? AND reading_progresses.is_current AND reading_progresses.status = ?", userID, models.StatusPaused).
+		Count(&stats.Paused)
+
+	r.db.WithContext(ctx).Table("reading_progresses").
+		Joins("JOIN books ON books.id = reading_progresses.book_id").
+		Where("books.user_id = ? AND reading_progresses.is_current AND reading_progresses.status = ?", userID, models.StatusDidNotFinish).
+		Count(&stats.DidNotFinish)
+
 	//  yearly finished
-	r.db.Table("reading_progresses").
+	r.db.WithContext(ctx).Table("reading_progresses").
 		Joins("JOIN books ON books.id = reading_progresses.book_id").
-		Where("books.user_id = ? AND reading_progresses.status = ? AND EXTRACT(YEAR FROM reading_progresses.last_updated) = ?", userID, "Finished", currentYear).
+		Where("books.user_id = ? AND reading_progresses.status = ? AND EXTRACT(YEAR FROM reading_progresses.finished_at) = ?", userID, models.StatusFinished, currentYear).
 		Count(&stats.BooksFinished)
 
 	// 4. monthly finished
-	r.db.Table("reading_progresses").
+	r.db.WithContext(ctx).Table("reading_progresses").
 		Joins("JOIN books ON books.id = reading_progresses.book_id").
-		Where("books.user_id = ? AND reading_progresses.status = ? AND EXTRACT(YEAR FROM reading_progresses.last_updated) = ? AND EXTRACT(MONTH FROM reading_progresses.last_updated) = ?", userID, "Finished", currentYear, currentMonth).
+		Where("books.user_id = ? AND reading_progresses.status = ? AND EXTRACT(YEAR FROM reading_progresses.finished_at) = ? AND EXTRACT(MONTH FROM reading_progresses.finished_at) = ?", userID, models.StatusFinished, currentYear, currentMonth).
 		Count(&stats.MonthlyFinished)
 
-	//  yearly target
-	var totalTarget int64
-	r.db.Model(&models.ReadingGoal{}).
-		Where("user_id = ? AND year = ?", userID, currentYear).
-		Select("COALESCE(SUM(target_books), 0)").Scan(&totalTarget)
-	stats.YearlyTarget = int(totalTarget)
+	//  yearly target: an explicit yearly goal wins, otherwise sum the monthly ones
+	var yGoal models.ReadingGoal
+	err := r.db.WithContext(ctx).Where("user_id = ? AND type = ? AND metric = ? AND year = ?",
+		userID, models.GoalTypeYearly, models.GoalMetricBooks, currentYear).First(&yGoal).Error
+	if err == nil {
+		stats.YearlyTarget = yGoal.TargetBooks
+	} else {
+		var totalTarget int64
+		r.db.WithContext(ctx).Model(&models.ReadingGoal{}).
+			Where("user_id = ? AND type = ? AND metric = ? AND year = ?", userID, models.GoalTypeMonthly, models.GoalMetricBooks, currentYear).
+			Select("COALESCE(SUM(target_books), 0)").Scan(&totalTarget)
+		stats.YearlyTarget = int(totalTarget)
+	}
 
 	//  current monthly target
 	var mGoal models.ReadingGoal
-	r.db.Where("user_id = ? AND year = ? AND month = ?", userID, currentYear, currentMonth).First(&mGoal)
+	r.db.WithContext(ctx).Where("user_id = ? AND type = ? AND metric = ? AND year = ? AND month = ?",
+		userID, models.GoalTypeMonthly, models.GoalMetricBooks, currentYear, currentMonth).First(&mGoal)
 	stats.MonthlyTarget = mGoal.TargetBooks
 
-	r.db.Model(&models.ReadingGoal{}).
-		Where("user_id = ? AND year = ?", userID, currentYear).
+	r.db.WithContext(ctx).Model(&models.ReadingGoal{}).
+		Where("user_id = ? AND type = ? AND metric = ? AND year = ?", userID, models.GoalTypeMonthly, models.GoalMetricBooks, currentYear).
 		Count(&stats.GoalsSetCount)
 
 	return stats, nil
 }
-
-func (r *bookRepository) SearchBooks(userID uint, query string) ([]models.Book, error) {
-	var books []models.Book
-	searchTerm := "%" + query + "%"
-	err := r.db.Preload("Progress").Where("user_id = ? AND title ILIKE ?", userID, searchTerm).Find(&books).Error
-	return books, err
-}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+)
+
+const searchResultLimit = 50
+
+// The search indexes in database/migrations must use the exact same
+// expressions, otherwise Postgres will not pick the GIN indexes up.
+const (
+	bookSearchDocument = "(setweight(to_tsvector('english', coalesce(books.title, '')), 'A') || " +
+		"setweight(to_tsvector('english', coalesce(books.author, '')), 'A') || " +
+		"setweight(to_tsvector('english', coalesce(books.genre, '')), 'C'))"
+	reviewSearchDocument = "to_tsvector('english', coalesce(reviews.comment, ''))"
+	isbnDigits           = "UPPER(regexp_replace(books.isbn, '[^0-9Xx]', '', 'g'))"
+)
+
+var nonISBNChars = regexp.MustCompile(`[^0-9Xx]`)
+
+// searchISBN turns the query into what stored ISBNs look like. Stored ISBNs
+// are bare ISBN-13s, so a query that is a valid ISBN-10 or a hyphenated
+// ISBN-13 is normalized the same way; anything else is just stripped.
+func searchISBN(query string) string {
+	isbn := strings.ToUpper(nonISBNChars.ReplaceAllString(query, ""))
+	if normalized, err := utils.NormalizeISBN(isbn); err == nil {
+		return normalized
+	}
+	return isbn
+}
+
+type bookSearchRow struct {
+	ID   uint
+	Rank float64
+}
+
+// SearchBooks ranks full-text matches over title, author, genre and the
+// user's review comments, boosts exact ISBN hits, and falls back to trigram
+// similarity on title and author so small typos still find the book.
+func (r *bookRepository) SearchBooks(ctx context.Context, userID uint, query string) ([]models.Book, error) {
+	query = strings.TrimSpace(query)
+	isbn := searchISBN(query)
+
+	sql := `
+WITH q AS (SELECT websearch_to_tsquery('english', @query) AS tsq)
+SELECT books.id AS id,
+	ts_rank(` + bookSearchDocument + `, q.tsq)
+	+ 0.5 * COALESCE((SELECT MAX(ts_rank(` + reviewSearchDocument + `, q.tsq)) FROM reviews WHERE reviews.book_id = books.id), 0)
+	+ 0.3 * GREATEST(similarity(books.title, @query), similarity(books.author, @query))
+	+ CASE WHEN @isbn <> '' AND ` + isbnDigits + ` = @isbn THEN 10 ELSE 0 END AS rank
+FROM books, q
+WHERE books.user_id = @user_id
+	AND (
+		` + bookSearchDocument + ` @@ q.tsq
+		OR EXISTS (SELECT 1 FROM reviews WHERE reviews.book_id = books.id AND ` + reviewSearchDocument + ` @@ q.tsq)
+		OR (@isbn <> '' AND ` + isbnDigits + ` = @isbn)
+		OR books.title % @query
+		OR books.author % @query
+	)
+ORDER BY rank DESC, books.id
+LIMIT @limit`
+
+	var rows []bookSearchRow
+	err := r.db.WithContext(ctx).Raw(sql, map[string]interface{}{
+		"query":   query,
+		"isbn":    isbn,
+		"user_id": userID,
+		"limit":   searchResultLimit,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return r.loadBooksInOrder(ctx, ids)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "testing"
+
+func TestSearchISBN(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"0618260307", "9780618260300"},
+		{"0-618-26030-7", "9780618260300"},
+		{"978-0-618-26030-0", "9780618260300"},
+		{"9780618260300", "9780618260300"},
+		{"080442957x", "9780804429573"},
+		{"hobbit 1937", "1937"},
+		{"tolkien", ""},
+	}
+	for _, tt := range tests {
+		if got := searchISBN(tt.query); got != tt.want {
+			t.Errorf("searchISBN(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,21 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"gorm.io/gorm"
 )
 
 type GoalRepository interface {
-	SaveGoal(goal *models.ReadingGoal) error
-	GetGoal(userID uint, year int, month int) (*models.ReadingGoal, error)
-	CountFinishedBooks(userID uint, year int, month int) (int64, error)
+	SaveGoal(ctx context.Context, goal *models.ReadingGoal) error
+	GetGoal(ctx context.Context, userID uint, year int, month int) (*models.ReadingGoal, error)
+	CountFinishedBooksBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error)
+	SumPagesReadBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error)
 
-	GetYearlyTotalTarget(userID uint, year int) (int, error)
+	GetYearlyTotalTarget(ctx context.Context, userID uint, year int) (int, error)
+	GetGoalsByUserID(ctx context.Context, userID uint) ([]models.ReadingGoal, error)
 }
 
 type goalRepository struct {
@@ -21,44 +26,65 @@ func NewGoalRepository(db *gorm.DB) GoalRepository {
 	return &goalRepository{db: db}
 }
 
-func (r *goalRepository) SaveGoal(goal *models.ReadingGoal) error {
+// SaveGoal updates the target when the user already has a goal of the same
+// type and metric over the same period, otherwise it creates a new one.
+func (r *goalRepository) SaveGoal(ctx context.Context, goal *models.ReadingGoal) error {
 	var existing models.ReadingGoal
-	// only monthly goals
-	err := r.db.Where("user_id = ? AND year = ? AND month = ?", goal.UserID, goal.Year, goal.Month).First(&existing).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND type = ? AND metric = ? AND start_date = ? AND end_date = ?",
+		goal.UserID, goal.Type, goal.Metric, goal.StartDate, goal.EndDate).First(&existing).Error
 
 	if err == nil {
-		return r.db.Model(&existing).Update("target_books", goal.TargetBooks).Error
+		goal.ID = existing.ID
+		return r.db.WithContext(ctx).Model(&existing).Updates(map[string]interface{}{
+			"target_books": goal.TargetBooks,
+			"target_pages": goal.TargetPages,
+		}).Error
 	}
-	return r.db.Create(goal).Error
+	return r.db.WithContext(ctx).Create(goal).Error
 }
 
-func (r *goalRepository) GetGoal(userID uint, year int, month int) (*models.ReadingGoal, error) {
+func (r *goalRepository) GetGoal(ctx context.Context, userID uint, year int, month int) (*models.ReadingGoal, error) {
 	var goal models.ReadingGoal
-	err := r.db.Where("user_id = ? AND year = ? AND month = ?", userID, year, month).First(&goal).Error
+	err := r.db.WithContext(ctx).Where("user_id = ? AND type = ? AND metric = ? AND year = ? AND month = ?",
+		userID, models.GoalTypeMonthly, models.GoalMetricBooks, year, month).First(&goal).Error
 	return &goal, err
 }
 
-func (r *goalRepository) CountFinishedBooks(userID uint, year int, month int) (int64, error) {
+// CountFinishedBooksBetween counts books finished in [start, end).
+func (r *goalRepository) CountFinishedBooksBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
 	var count int64
-	query := r.db.Table("reading_progresses").
+	err := r.db.WithContext(ctx).Table("reading_progresses").
 		Joins("JOIN books ON books.id = reading_progresses.book_id").
-		Where("books.user_id = ? AND reading_progresses.status = ?", userID, "Finished").
-		Where("EXTRACT(YEAR FROM reading_progresses.last_updated) = ?", year)
-
-	if month > 0 {
-		query = query.Where("EXTRACT(MONTH FROM reading_progresses.last_updated) = ?", month)
-	}
-
-	err := query.Count(&count).Error
+		Where("books.user_id = ? AND reading_progresses.status = ?", userID, models.StatusFinished).
+		Where("reading_progresses.finished_at >= ? AND reading_progresses.finished_at < ?", start, end).
+		Count(&count).Error
 	return count, err
 }
 
-func (r *goalRepository) GetYearlyTotalTarget(userID uint, year int) (int, error) {
+// SumPagesReadBetween adds up the pages covered by reading sessions that ended in [start, end).
+func (r *goalRepository) SumPagesReadBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Table("reading_sessions").
+		Joins("JOIN books ON books.id = reading_sessions.book_id").
+		Where("books.user_id = ?", userID).
+		Where("reading_sessions.ended_at >= ? AND reading_sessions.ended_at < ?", start, end).
+		Select("COALESCE(SUM(GREATEST(reading_sessions.end_page - reading_sessions.start_page, 0)), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *goalRepository) GetYearlyTotalTarget(ctx context.Context, userID uint, year int) (int, error) {
 	var total int64
 
-	err := r.db.Model(&models.ReadingGoal{}).
-		Where("user_id = ? AND year = ?", userID, year).
+	err := r.db.WithContext(ctx).Model(&models.ReadingGoal{}).
+		Where("user_id = ? AND type = ? AND metric = ? AND year = ?", userID, models.GoalTypeMonthly, models.GoalMetricBooks, year).
 		Select("COALESCE(SUM(target_books), 0)").
 		Scan(&total).Error
 	return int(total), err
 }
+
+func (r *goalRepository) GetGoalsByUserID(ctx context.Context, userID uint) ([]models.ReadingGoal, error) {
+	var goals []models.ReadingGoal
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("start_date asc, id asc").Find(&goals).Error
+	return goals, err
+}
@@ -1,13 +1,18 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"gorm.io/gorm"
 )
 
 type ProgressRepository interface {
-	GetByBookID(bookID uint) (*models.ReadingProgress, error)
-	Save(progress *models.ReadingProgress) error
+	GetByBookID(ctx context.Context, bookID uint) (*models.ReadingProgress, error)
+	Save(ctx context.Context, progress *models.ReadingProgress) error
+	StartNewRead(ctx context.Context, current *models.ReadingProgress, next *models.ReadingProgress) error
+	SaveWithSession(ctx context.Context, progress *models.ReadingProgress, session *models.ReadingSession) error
+	GetSessionsByBookID(ctx context.Context, bookID uint) ([]models.ReadingSession, error)
 }
 
 type progressRepository struct {
@@ -18,12 +23,44 @@ func NewProgressRepository(db *gorm.DB) ProgressRepository {
 	return &progressRepository{db: db}
 }
 
-func (r *progressRepository) GetByBookID(bookID uint) (*models.ReadingProgress, error) {
+func (r *progressRepository) GetByBookID(ctx context.Context, bookID uint) (*models.ReadingProgress, error) {
 	var progress models.ReadingProgress
-	err := r.db.Where("book_id = ?", bookID).First(&progress).Error
+	err := r.db.WithContext(ctx).Where("book_id = ? AND is_current", bookID).First(&progress).Error
 	return &progress, err
 }
 
-func (r *progressRepository) Save(p *models.ReadingProgress) error {
-	return r.db.Save(p).Error
+func (r *progressRepository) Save(ctx context.Context, p *models.ReadingProgress) error {
+	return r.db.WithContext(ctx).Save(p).Error
+}
+
+// StartNewRead retires the current read-through and opens the next one together.
+func (r *progressRepository) StartNewRead(ctx context.Context, current *models.ReadingProgress, next *models.ReadingProgress) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(current).Update("is_current", false).Error; err != nil {
+			return err
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// SaveWithSession writes the progress and the session that produced it
+// together. Either may be nil.
+func (r *progressRepository) SaveWithSession(ctx context.Context, progress *models.ReadingProgress, session *models.ReadingSession) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if progress != nil {
+			if err := tx.Save(progress).Error; err != nil {
+				return err
+			}
+		}
+		if session != nil {
+			return tx.Create(session).Error
+		}
+		return nil
+	})
+}
+
+func (r *progressRepository) GetSessionsByBookID(ctx context.Context, bookID uint) ([]models.ReadingSession, error) {
+	var sessions []models.ReadingSession
+	err := r.db.WithContext(ctx).Where("book_id = ?", bookID).Order("started_at asc, id asc").Find(&sessions).Error
+	return sessions, err
 }
@@ -1,15 +1,26 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"gorm.io/gorm"
 )
 
 type ReviewRepository interface {
-	CreateReview(review *models.Review) error
-	GetReviewsByBookID(bookID uint) ([]models.Review, error)
-	GetReviewByBookID(bookID uint) (*models.Review, error)
-	GetReviewsByISBN(isbn string) ([]models.Review, error)
+	CreateReview(ctx context.Context, review *models.Review) error
+	GetReviewByBookID(ctx context.Context, bookID uint) (*models.Review, error)
+	GetReviewByReadThrough(ctx context.Context, readThroughID uint) (*models.Review, error)
+	GetReviewByID(ctx context.Context, id uint) (*models.Review, error)
+	GetReviewSummary(ctx context.Context, scope ReviewScope) (dto.ReviewSummary, error)
+	GetReviewFeed(ctx context.Context, scope ReviewScope, sort string, limit int, offset int) ([]dto.PublicReview, error)
+	UpdateReview(ctx context.Context, review *models.Review, previous *models.ReviewRevision) error
+	DeleteReview(ctx context.Context, id uint) error
+	GetReviewHistory(ctx context.Context, reviewID uint) ([]models.ReviewRevision, error)
+	AddVote(ctx context.Context, vote *models.ReviewVote) error
+	RemoveVote(ctx context.Context, reviewID uint, userID uint) error
+	GetReviewsByUserID(ctx context.Context, userID uint) ([]models.Review, error)
 }
 
 type reviewRepository struct {
@@ -20,34 +31,162 @@ func NewReviewRepository(db *gorm.DB) ReviewRepository {
 	return &reviewRepository{db: db}
 }
 
-func (r *reviewRepository) CreateReview(review *models.Review) error {
-	return r.db.Create(review).Error
+func (r *reviewRepository) CreateReview(ctx context.Context, review *models.Review) error {
+	return r.db.WithContext(ctx).Create(review).Error
 }
 
-func (r *reviewRepository) GetReviewsByBookID(bookID uint) ([]models.Review, error) {
-	var reviews []models.Review
-	err := r.db.Where("book_id = ?", bookID).Find(&reviews).Error
-	return reviews, err
+func (r *reviewRepository) GetReviewByBookID(ctx context.Context, bookID uint) (*models.Review, error) {
+	var review models.Review
+	err := r.db.WithContext(ctx).Where("book_id = ?", bookID).First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
 }
-func (r *reviewRepository) GetReviewByBookID(bookID uint) (*models.Review, error) {
+func (r *reviewRepository) GetReviewByReadThrough(ctx context.Context, readThroughID uint) (*models.Review, error) {
 	var review models.Review
-	err := r.db.Where("book_id = ?", bookID).First(&review).Error
+	err := r.db.WithContext(ctx).Where("reading_progress_id = ?", readThroughID).First(&review).Error
 	if err != nil {
 		return nil, err
 	}
 	return &review, nil
 }
-func (r *reviewRepository) GetReviewsByISBN(isbn string) ([]models.Review, error) {
-	var reviews []models.Review
 
-	err := r.db.
-		Preload("Book.User").
-		Table("reviews").
-		Select("reviews.*").
-		Joins("JOIN books ON books.id = reviews.book_id").
-		Where("books.isbn = ? AND books.isbn != ''", isbn).
-		Order("reviews.created_at desc").
-		Find(&reviews).Error
+// ReviewScope selects the reviews shown together: every edition of a work, or
+// a single library entry for books not linked to the catalog. Only reviews
+// ViewerID is allowed to see are included.
+type ReviewScope struct {
+	WorkID   *uint
+	BookID   uint
+	ViewerID uint
+}
 
+func (sc ReviewScope) apply(db *gorm.DB) *gorm.DB {
+	db = db.Joins("JOIN books ON books.id = reviews.book_id").
+		Where(`reviews.user_id = ? OR reviews.visibility = ? OR (reviews.visibility = ? AND EXISTS (
+			SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = reviews.user_id AND follows.approved))`,
+			sc.ViewerID, models.VisibilityPublic, models.VisibilityFollowers, sc.ViewerID)
+	if sc.WorkID != nil {
+		return db.Where("books.work_id = ?", *sc.WorkID)
+	}
+	return db.Where("books.id = ?", sc.BookID)
+}
+
+func (r *reviewRepository) GetReviewByID(ctx context.Context, id uint) (*models.Review, error) {
+	var review models.Review
+	err := r.db.WithContext(ctx).Preload("Book").First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetReviewSummary(ctx context.Context, scope ReviewScope) (dto.ReviewSummary, error) {
+	summary := dto.ReviewSummary{Histogram: map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+
+	var buckets []struct {
+		Rating int
+		Count  int64
+	}
+	err := scope.apply(r.db.WithContext(ctx).Table("reviews")).
+		Select("reviews.rating AS rating, COUNT(*) AS count").
+		Group("reviews.rating").
+		Scan(&buckets).Error
+	if err != nil {
+		return summary, err
+	}
+
+	var total int64
+	for _, b := range buckets {
+		summary.Histogram[b.Rating] = b.Count
+		summary.RatingCount += b.Count
+		total += int64(b.Rating) * b.Count
+	}
+	if summary.RatingCount > 0 {
+		summary.AverageRating = float64(total) / float64(summary.RatingCount)
+	}
+	return summary, nil
+}
+
+var reviewFeedOrder = map[string]string{
+	"newest":  "reviews.created_at DESC, reviews.id DESC",
+	"helpful": "helpful_count DESC, reviews.created_at DESC, reviews.id DESC",
+}
+
+func (r *reviewRepository) GetReviewFeed(ctx context.Context, scope ReviewScope, sort string, limit int, offset int) ([]dto.PublicReview, error) {
+	order, ok := reviewFeedOrder[sort]
+	if !ok {
+		order = reviewFeedOrder["newest"]
+	}
+
+	var reviews []dto.PublicReview
+	err := scope.apply(r.db.WithContext(ctx).Table("reviews")).
+		Joins("JOIN users ON users.id = reviews.user_id").
+		Select(`reviews.id, reviews.rating, reviews.comment, reviews.created_at,
+			reviews.edited_at, reviews.is_spoiler, reviews.user_id AS reviewer_id,
+			COALESCE(NULLIF(users.display_name, ''), SPLIT_PART(users.name, ' ', 1)) AS reviewer_name,
+			(SELECT COUNT(*) FROM review_votes WHERE review_votes.review_id = reviews.id) AS helpful_count,
+			reviews.user_id = ? AS is_mine,
+			EXISTS (SELECT 1 FROM review_votes WHERE review_votes.review_id = reviews.id AND review_votes.user_id = ?) AS voted_helpful`,
+			scope.ViewerID, scope.ViewerID).
+		Order(order).
+		Limit(limit).
+		Offset(offset).
+		Scan(&reviews).Error
+	return reviews, err
+}
+
+// UpdateReview saves the edited review together with the version it replaces,
+// if the text changed.
+func (r *reviewRepository) UpdateReview(ctx context.Context, review *models.Review, previous *models.ReviewRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if previous != nil {
+			if err := tx.Create(previous).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(review).Updates(map[string]interface{}{
+			"rating":     review.Rating,
+			"comment":    review.Comment,
+			"edited_at":  review.EditedAt,
+			"visibility": review.Visibility,
+			"is_spoiler": review.IsSpoiler,
+		}).Error
+	})
+}
+
+func (r *reviewRepository) DeleteReview(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("review_id = ?", id).Delete(&models.ReviewRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", id).Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Review{}, id).Error
+	})
+}
+
+func (r *reviewRepository) GetReviewHistory(ctx context.Context, reviewID uint) ([]models.ReviewRevision, error) {
+	var revisions []models.ReviewRevision
+	err := r.db.WithContext(ctx).Where("review_id = ?", reviewID).Order("created_at desc, id desc").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *reviewRepository) AddVote(ctx context.Context, vote *models.ReviewVote) error {
+	return r.db.WithContext(ctx).Where(models.ReviewVote{ReviewID: vote.ReviewID, UserID: vote.UserID}).FirstOrCreate(vote).Error
+}
+
+func (r *reviewRepository) RemoveVote(ctx context.Context, reviewID uint, userID uint) error {
+	return r.db.WithContext(ctx).Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewVote{}).Error
+}
+
+func (r *reviewRepository) GetReviewsByUserID(ctx context.Context, userID uint) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.db.WithContext(ctx).
+		Preload("Book").
+		Where("user_id = ?", userID).
+		Order("created_at asc, id asc").
+		Find(&reviews).Error
 	return reviews, err
 }
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"gorm.io/gorm"
+)
+
+type ShelfRepository interface {
+	CreateShelf(ctx context.Context, shelf *models.Shelf) error
+	GetShelvesByUserID(ctx context.Context, userID uint) ([]dto.ShelfSummary, error)
+	GetShelf(ctx context.Context, id uint, userID uint) (*models.Shelf, error)
+	FindShelfByName(ctx context.Context, userID uint, name string) (*models.Shelf, error)
+	UpdateShelf(ctx context.Context, shelf *models.Shelf) error
+	DeleteShelf(ctx context.Context, shelf *models.Shelf) error
+	AddBooksToShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error
+	RemoveBooksFromShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error
+
+	GetBooksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Book, error)
+
+	GetTagsByUserID(ctx context.Context, userID uint) ([]dto.TagSummary, error)
+	FindOrCreateTags(ctx context.Context, userID uint, names []string) ([]models.Tag, error)
+	GetTagsByNames(ctx context.Context, userID uint, names []string) ([]models.Tag, error)
+	AddTagsToBooks(ctx context.Context, books []models.Book, tags []models.Tag) error
+	RemoveTagsFromBooks(ctx context.Context, books []models.Book, tags []models.Tag) error
+}
+
+type shelfRepository struct {
+	db *gorm.DB
+}
+
+func NewShelfRepository(db *gorm.DB) ShelfRepository {
+	return &shelfRepository{db: db}
+}
+
+func (r *shelfRepository) CreateShelf(ctx context.Context, shelf *models.Shelf) error {
+	return r.db.WithContext(ctx).Create(shelf).Error
+}
+
+func (r *shelfRepository) GetShelvesByUserID(ctx context.Context, userID uint) ([]dto.ShelfSummary, error) {
+	var shelves []dto.ShelfSummary
+	err := r.db.WithContext(ctx).Table("shelves").
+		Select("shelves.id, shelves.name, shelves.description, shelves.created_at, COUNT(book_shelves.book_id) AS book_count").
+		Joins("LEFT JOIN book_shelves ON book_shelves.shelf_id = shelves.id").
+		Where("shelves.user_id = ?", userID).
+		Group("shelves.id").
+		Order("LOWER(shelves.name) asc").
+		Scan(&shelves).Error
+	return shelves, err
+}
+
+func (r *shelfRepository) GetShelf(ctx context.Context, id uint, userID uint) (*models.Shelf, error) {
+	var shelf models.Shelf
+	err := r.db.WithContext(ctx).
+		Preload("Books", func(db *gorm.DB) *gorm.DB { return db.Order("LOWER(books.title) asc") }).
+		Preload("Books.Progress", currentReadOnly).
+		Preload("Books.Tags").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&shelf).Error
+	return &shelf, err
+}
+
+func (r *shelfRepository) FindShelfByName(ctx context.Context, userID uint, name string) (*models.Shelf, error) {
+	var shelf models.Shelf
+	err := r.db.WithContext(ctx).Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&shelf).Error
+	return &shelf, err
+}
+
+func (r *shelfRepository) UpdateShelf(ctx context.Context, shelf *models.Shelf) error {
+	return r.db.WithContext(ctx).Model(shelf).Updates(map[string]interface{}{
+		"name":        shelf.Name,
+		"description": shelf.Description,
+	}).Error
+}
+
+// DeleteShelf removes the shelf and its memberships; the books themselves stay.
+func (r *shelfRepository) DeleteShelf(ctx context.Context, shelf *models.Shelf) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(shelf).Association("Books").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(shelf).Error
+	})
+}
+
+func (r *shelfRepository) AddBooksToShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error {
+	return r.db.WithContext(ctx).Model(shelf).Omit("Books.*").Association("Books").Append(books)
+}
+
+func (r *shelfRepository) RemoveBooksFromShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error {
+	return r.db.WithContext(ctx).Model(shelf).Association("Books").Delete(books)
+}
+
+func (r *shelfRepository) GetBooksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Book, error) {
+	var books []models.Book
+	err := r.db.WithContext(ctx).Where("id IN ? AND user_id = ?", ids, userID).Find(&books).Error
+	return books, err
+}
+
+func (r *shelfRepository) GetTagsByUserID(ctx context.Context, userID uint) ([]dto.TagSummary, error) {
+	var tags []dto.TagSummary
+	err := r.db.WithContext(ctx).Table("tags").
+		Select("tags.name, COUNT(book_tags.book_id) AS book_count").
+		Joins("LEFT JOIN book_tags ON book_tags.tag_id = tags.id").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id").
+		Order("tags.name asc").
+		Scan(&tags).Error
+	return tags, err
+}
+
+func (r *shelfRepository) FindOrCreateTags(ctx context.Context, userID uint, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tag := models.Tag{UserID: userID, Name: name}
+		if err := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", userID, name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *shelfRepository) GetTagsByNames(ctx context.Context, userID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).Where("user_id = ? AND name IN ?", userID, names).Find(&tags).Error
+	return tags, err
+}
+
+func (r *shelfRepository) AddTagsToBooks(ctx context.Context, books []models.Book, tags []models.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range books {
+			if err := tx.Model(&books[i]).Omit("Tags.*").Association("Tags").Append(tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *shelfRepository) RemoveTagsFromBooks(ctx context.Context, books []models.Book, tags []models.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range books {
+			if err := tx.Model(&books[i]).Association("Tags").Delete(tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
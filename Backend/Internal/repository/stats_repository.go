@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"gorm.io/gorm"
+)
+
+type StatsRepository interface {
+	GetFinishedReads(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.FinishedRead, error)
+	GetPagesPerDay(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.ReadingDay, error)
+	GetReadingDays(ctx context.Context, userID uint) ([]time.Time, error)
+	CountDidNotFinish(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error)
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// GetFinishedReads returns every read-through finished in [start, end).
+func (r *statsRepository) GetFinishedReads(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.FinishedRead, error) {
+	var reads []dto.FinishedRead
+	err := r.db.WithContext(ctx).Table("reading_progresses").
+		Joins("JOIN books ON books.id = reading_progresses.book_id").
+		Joins("LEFT JOIN reviews ON reviews.reading_progress_id = reading_progresses.id").
+		Where("books.user_id = ? AND reading_progresses.status = ?", userID, models.StatusFinished).
+		Where("reading_progresses.finished_at >= ? AND reading_progresses.finished_at < ?", start, end).
+		Select(`books.id AS book_id, books.title, books.author, books.genre, books.total_pages,
+			COALESCE(reviews.rating, 0) AS rating,
+			reading_progresses.started_at, reading_progresses.finished_at`).
+		Order("reading_progresses.finished_at").
+		Scan(&reads).Error
+	return reads, err
+}
+
+// GetPagesPerDay sums the pages of reading sessions that ended in [start, end),
+// per day. Days without pages are left out.
+func (r *statsRepository) GetPagesPerDay(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.ReadingDay, error) {
+	var days []dto.ReadingDay
+	err := r.db.WithContext(ctx).Table("reading_sessions").
+		Joins("JOIN books ON books.id = reading_sessions.book_id").
+		Where("books.user_id = ?", userID).
+		Where("reading_sessions.ended_at >= ? AND reading_sessions.ended_at < ?", start, end).
+		Select("DATE(reading_sessions.ended_at) AS day, SUM(GREATEST(reading_sessions.end_page - reading_sessions.start_page, 0)) AS pages").
+		Group("DATE(reading_sessions.ended_at)").
+		Having("SUM(GREATEST(reading_sessions.end_page - reading_sessions.start_page, 0)) > 0").
+		Order("day").
+		Scan(&days).Error
+	return days, err
+}
+
+// GetReadingDays lists every day the user read at least one page, oldest first.
+func (r *statsRepository) GetReadingDays(ctx context.Context, userID uint) ([]time.Time, error) {
+	var days []time.Time
+	err := r.db.WithContext(ctx).Table("reading_sessions").
+		Joins("JOIN books ON books.id = reading_sessions.book_id").
+		Where("books.user_id = ? AND reading_sessions.end_page > reading_sessions.start_page", userID).
+		Order("DATE(reading_sessions.ended_at)").
+		Pluck("DISTINCT DATE(reading_sessions.ended_at)", &days).Error
+	return days, err
+}
+
+// CountDidNotFinish counts the read-throughs marked Did Not Finish in
+// [start, end). An abandoned read has no finish date, so its last update
+// stands in for when it was given up.
+func (r *statsRepository) CountDidNotFinish(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("reading_progresses").
+		Joins("JOIN books ON books.id = reading_progresses.book_id").
+		Where("books.user_id = ? AND reading_progresses.status = ?", userID, models.StatusDidNotFinish).
+		Where("reading_progresses.last_updated >= ? AND reading_progresses.last_updated < ?", start, end).
+		Count(&count).Error
+	return count, err
+}
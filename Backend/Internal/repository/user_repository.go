@@ -1,14 +1,23 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	CreateUser(user *models.User) error
-	FindByEmail(email string) (*models.User, error)
-	FindByID(id uint) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	UpdateSettings(ctx context.Context, user *models.User) error
+	Follow(ctx context.Context, follow *models.Follow) error
+	Unfollow(ctx context.Context, followerID uint, followeeID uint) error
+	IsFollowing(ctx context.Context, followerID uint, followeeID uint) (bool, error)
+	ApproveFollow(ctx context.Context, followerID uint, followeeID uint) error
+	GetFollowRequests(ctx context.Context, followeeID uint) ([]dto.FollowRequest, error)
 }
 
 type userRepository struct {
@@ -19,24 +28,74 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) CreateUser(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindByID(id uint) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func (r *userRepository) UpdateSettings(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
+		"display_name":              user.DisplayName,
+		"default_review_visibility": user.DefaultReviewVisibility,
+	}).Error
+}
+
+func (r *userRepository) Follow(ctx context.Context, follow *models.Follow) error {
+	return r.db.WithContext(ctx).Where(models.Follow{FollowerID: follow.FollowerID, FolloweeID: follow.FolloweeID}).FirstOrCreate(follow).Error
+}
+
+func (r *userRepository) Unfollow(ctx context.Context, followerID uint, followeeID uint) error {
+	return r.db.WithContext(ctx).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&models.Follow{}).Error
+}
+
+// IsFollowing only counts follows the followee has approved.
+func (r *userRepository) IsFollowing(ctx context.Context, followerID uint, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Follow{}).Where("follower_id = ? AND followee_id = ? AND approved", followerID, followeeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) ApproveFollow(ctx context.Context, followerID uint, followeeID uint) error {
+	result := r.db.WithContext(ctx).Model(&models.Follow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Update("approved", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetFollowRequests lists the follows waiting for followeeID's approval,
+// oldest first.
+func (r *userRepository) GetFollowRequests(ctx context.Context, followeeID uint) ([]dto.FollowRequest, error) {
+	var requests []dto.FollowRequest
+	err := r.db.WithContext(ctx).Table("follows").
+		Joins("JOIN users ON users.id = follows.follower_id").
+		Select(`follows.follower_id,
+			COALESCE(NULLIF(users.display_name, ''), SPLIT_PART(users.name, ' ', 1)) AS follower_name,
+			follows.created_at AS requested_at`).
+		Where("follows.followee_id = ? AND NOT follows.approved", followeeID).
+		Order("follows.created_at, follows.id").
+		Scan(&requests).Error
+	return requests, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"gorm.io/gorm"
+)
+
+type WorkRepository interface {
+	FindOrCreateWork(ctx context.Context, work *models.Work) error
+	FindEditionByISBN(ctx context.Context, isbn string) (*models.Edition, error)
+	FindOrCreateEdition(ctx context.Context, edition *models.Edition) error
+	GetWork(ctx context.Context, id uint) (*models.Work, error)
+	GetWorkStats(ctx context.Context, workID uint) (dto.WorkStats, error)
+}
+
+type workRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkRepository(db *gorm.DB) WorkRepository {
+	return &workRepository{db: db}
+}
+
+// FindOrCreateWork loads the work with the same key, creating it from work's
+// fields if it does not exist yet.
+func (r *workRepository) FindOrCreateWork(ctx context.Context, work *models.Work) error {
+	return r.db.WithContext(ctx).Where(models.Work{Key: work.Key}).Attrs(*work).FirstOrCreate(work).Error
+}
+
+func (r *workRepository) FindEditionByISBN(ctx context.Context, isbn string) (*models.Edition, error) {
+	var edition models.Edition
+	err := r.db.WithContext(ctx).Where("isbn = ?", isbn).First(&edition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &edition, nil
+}
+
+func (r *workRepository) FindOrCreateEdition(ctx context.Context, edition *models.Edition) error {
+	return r.db.WithContext(ctx).Where(models.Edition{ISBN: edition.ISBN}).Attrs(*edition).FirstOrCreate(edition).Error
+}
+
+func (r *workRepository) GetWork(ctx context.Context, id uint) (*models.Work, error) {
+	var work models.Work
+	err := r.db.WithContext(ctx).Preload("Editions", func(db *gorm.DB) *gorm.DB { return db.Order("publication_year asc") }).
+		First(&work, id).Error
+	return &work, err
+}
+
+// GetWorkStats aggregates readers and public reviews across every edition of a work.
+func (r *workRepository) GetWorkStats(ctx context.Context, workID uint) (dto.WorkStats, error) {
+	var stats dto.WorkStats
+	err := r.db.WithContext(ctx).Table("books").
+		Select("COUNT(DISTINCT books.user_id) AS readers, COUNT(reviews.id) AS review_count, COALESCE(AVG(reviews.rating), 0) AS average_rating").
+		Joins("LEFT JOIN reviews ON reviews.book_id = books.id AND reviews.visibility = ?", models.VisibilityPublic).
+		Where("books.work_id = ?", workID).
+		Scan(&stats).Error
+	return stats, err
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/handlers"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/middleware"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,17 +14,39 @@ func RegisterRoutes(
 	progressHandler *handlers.ProgressHandler,
 	reviewHandler *handlers.ReviewHandler,
 	goalHandler *handlers.GoalHandler,
+	importHandler *handlers.ImportHandler,
+	exportHandler *handlers.ExportHandler,
+	shelfHandler *handlers.ShelfHandler,
+	workHandler *handlers.WorkHandler,
+	statsHandler *handlers.StatsHandler,
+	reportHandler *handlers.ReportHandler,
+	healthHandler *handlers.HealthHandler,
+	tokenRepo repository.TokenRepository,
+	jwtSecret string,
 ) {
 
+	r.GET("/healthz", healthHandler.Liveness)
+	r.GET("/readyz", healthHandler.Readiness)
+
 	api := r.Group("/api")
 	{
 
 		api.POST("/register", userHandler.Register)
 		api.POST("/login", userHandler.Login)
+		api.POST("/token/refresh", userHandler.RefreshToken)
 
 		protected := api.Group("/")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(tokenRepo, jwtSecret))
 		{
+			protected.POST("/logout", userHandler.Logout)
+			protected.GET("/me/settings", userHandler.GetSettings)
+			protected.PUT("/me/settings", userHandler.UpdateSettings)
+			protected.POST("/users/:id/follow", userHandler.Follow)
+			protected.DELETE("/users/:id/follow", userHandler.Unfollow)
+			protected.GET("/me/follow-requests", userHandler.GetFollowRequests)
+			protected.POST("/me/followers/:id", userHandler.ApproveFollower)
+			protected.DELETE("/me/followers/:id", userHandler.RemoveFollower)
+
 			protected.POST("/books", bookHandler.AddBook)
 			protected.GET("/books", bookHandler.ListBooks)
 			protected.GET("/books/:id", bookHandler.GetBook)
@@ -31,15 +54,45 @@ func RegisterRoutes(
 			protected.DELETE("/books/:id", bookHandler.DeleteBook)
 			protected.GET("/books/:id/progress", progressHandler.GetProgress)
 			protected.PUT("/books/:id/progress", progressHandler.UpdateProgress)
+			protected.PUT("/books/:id/progress/dates", progressHandler.UpdateDates)
+			protected.POST("/books/:id/rereads", progressHandler.StartReread)
+			protected.POST("/books/:id/sessions", progressHandler.LogSession)
+			protected.GET("/books/:id/sessions", progressHandler.GetSessions)
 			protected.POST("/books/:id/reviews", reviewHandler.AddReview)
 			protected.GET("/books/:id/reviews", reviewHandler.GetReviews)
+			protected.PUT("/books/:id/reviews/:reviewId", reviewHandler.UpdateReview)
+			protected.DELETE("/books/:id/reviews/:reviewId", reviewHandler.DeleteReview)
+			protected.GET("/books/:id/reviews/:reviewId/history", reviewHandler.GetReviewHistory)
+			protected.GET("/me/reviews", reviewHandler.GetMyReviews)
+			protected.POST("/reviews/:id/helpful", reviewHandler.MarkHelpful)
+			protected.DELETE("/reviews/:id/helpful", reviewHandler.UnmarkHelpful)
 
 			protected.GET("/dashboard", bookHandler.GetDashboard)
+			protected.GET("/stats", statsHandler.GetStats)
+			protected.GET("/reports/year/:year", reportHandler.GetYearReport)
 			protected.GET("/books/search", bookHandler.SearchBooks)
+			protected.GET("/lookup", bookHandler.Lookup)
+			protected.GET("/works/:id", workHandler.GetWork)
 
 			protected.POST("/goals", goalHandler.SetGoal)
+			protected.GET("/goals", goalHandler.ListGoals)
 
 			protected.GET("/goals/:year/:month", goalHandler.GetGoalStatus)
+
+			protected.POST("/shelves", shelfHandler.CreateShelf)
+			protected.GET("/shelves", shelfHandler.ListShelves)
+			protected.GET("/shelves/:id", shelfHandler.GetShelf)
+			protected.PUT("/shelves/:id", shelfHandler.UpdateShelf)
+			protected.DELETE("/shelves/:id", shelfHandler.DeleteShelf)
+			protected.POST("/shelves/:id/books", shelfHandler.AddBooks)
+			protected.DELETE("/shelves/:id/books", shelfHandler.RemoveBooks)
+
+			protected.GET("/tags", shelfHandler.ListTags)
+			protected.POST("/tags/books", shelfHandler.TagBooks)
+			protected.DELETE("/tags/books", shelfHandler.UntagBooks)
+
+			protected.POST("/import/goodreads", importHandler.ImportGoodreads)
+			protected.GET("/export", exportHandler.Export)
 		}
 	}
 }
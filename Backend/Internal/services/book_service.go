@@ -1,40 +1,80 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/catalog"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidYearRange    = errors.New("year_from cannot be after year_to")
+	ErrInvalidStatusFilter = errors.New("unknown status filter")
+	ErrMissingBookDetails  = errors.New("title and author are required")
+	ErrCatalogUnavailable  = errors.New("book lookup is not configured")
+	ErrDuplicateISBN       = errors.New("a book with this ISBN is already in your library")
+	ErrDuplicateTitle      = errors.New("this book title and author already exists in your library")
+	ErrBookNotFound        = errors.New("book not found")
 )
 
 type BookService interface {
-	CreateBook(userID uint, req dto.CreateBookRequest) (*models.Book, error)
-	FetchBooks(userID uint) ([]models.Book, error)
-	UpdateBook(bookID uint, userID uint, req dto.UpdateBookRequest) error
-	DeleteBook(bookID uint, userID uint) error
-	GetSingleBook(bookID uint, userID uint) (*models.Book, error)
-	GetDashboardStats(userID uint) (dto.DashboardStats, error)
-	SearchMyBooks(userID uint, query string) ([]models.Book, error)
+	CreateBook(ctx context.Context, userID uint, req dto.CreateBookRequest) (*models.Book, error)
+	FetchBooks(ctx context.Context, userID uint) ([]models.Book, error)
+	ListBooks(ctx context.Context, userID uint, query dto.BookListQuery) ([]models.Book, string, error)
+	UpdateBook(ctx context.Context, bookID uint, userID uint, req dto.UpdateBookRequest) error
+	DeleteBook(ctx context.Context, bookID uint, userID uint) error
+	GetSingleBook(ctx context.Context, bookID uint, userID uint) (*dto.BookDetail, error)
+	GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error)
+	SearchMyBooks(ctx context.Context, userID uint, query string) ([]models.Book, error)
+	LookupISBN(ctx context.Context, isbn string) (*catalog.BookMetadata, error)
+	LookupTitle(ctx context.Context, title string) ([]catalog.BookMetadata, error)
 }
 
 type bookService struct {
-	repo repository.BookRepository
+	repo     repository.BookRepository
+	workRepo repository.WorkRepository
+	catalog  catalog.CatalogProvider
 }
 
-func NewBookService(repo repository.BookRepository) BookService {
-	return &bookService{repo: repo}
+// NewBookService takes an optional catalog; with a nil one books must be
+// entered by hand.
+func NewBookService(repo repository.BookRepository, workRepo repository.WorkRepository, provider catalog.CatalogProvider) BookService {
+	return &bookService{repo: repo, workRepo: workRepo, catalog: provider}
 }
-func (s *bookService) CreateBook(userID uint, req dto.CreateBookRequest) (*models.Book, error) {
+func (s *bookService) CreateBook(ctx context.Context, userID uint, req dto.CreateBookRequest) (*models.Book, error) {
+
+	isbn, err := utils.NormalizeISBN(req.ISBN)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, req.ISBN)
+	}
+	req.ISBN = isbn
 
-	existing, _ := s.repo.FindDuplicate(userID, req.Title, req.Author, req.ISBN)
+	if req.ISBN != "" && s.catalog != nil && needsAutoFill(req) {
+		meta, err := s.catalog.LookupISBN(ctx, req.ISBN)
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not look up ISBN %s: %v", ErrMissingBookDetails, req.ISBN, err)
+		}
+		fillFromCatalog(&req, meta)
+	}
+	if req.Title == "" || req.Author == "" {
+		return nil, ErrMissingBookDetails
+	}
+
+	existing, _ := s.repo.FindDuplicate(ctx, userID, req.Title, req.Author, req.ISBN)
 	if existing != nil {
 
 		if req.ISBN != "" && existing.ISBN == req.ISBN {
-			return nil, errors.New("a book with this ISBN is already in your library")
+			return nil, ErrDuplicateISBN
 		}
 
-		return nil, errors.New("this book title and author already exists in your library")
+		return nil, ErrDuplicateTitle
 	}
 
 	book := &models.Book{
@@ -46,15 +86,31 @@ func (s *bookService) CreateBook(userID uint, req dto.CreateBookRequest) (*model
 		PublicationYear: req.PublicationYear,
 		TotalPages:      req.TotalPages,
 	}
+	if err := linkToCatalog(ctx, s.workRepo, book); err != nil {
+		return nil, err
+	}
 
-	err := s.repo.CreateBook(book)
-	return book, err
+	if err := s.repo.CreateBook(ctx, book); err != nil {
+		return nil, err
+	}
+	metrics.BooksAdded.WithLabelValues("manual").Inc()
+	return book, nil
+}
+func (s *bookService) FetchBooks(ctx context.Context, userID uint) ([]models.Book, error) {
+	return s.repo.GetBooksByUserID(ctx, userID)
 }
-func (s *bookService) FetchBooks(userID uint) ([]models.Book, error) {
-	return s.repo.GetBooksByUserID(userID)
+
+func (s *bookService) ListBooks(ctx context.Context, userID uint, query dto.BookListQuery) ([]models.Book, string, error) {
+	if query.YearFrom > 0 && query.YearTo > 0 && query.YearFrom > query.YearTo {
+		return nil, "", ErrInvalidYearRange
+	}
+	if query.Status != "" && !models.ReadingStatus(query.Status).Valid() {
+		return nil, "", ErrInvalidStatusFilter
+	}
+	return s.repo.ListBooks(ctx, userID, query)
 }
 
-func (s *bookService) UpdateBook(bookID uint, userID uint, req dto.UpdateBookRequest) error {
+func (s *bookService) UpdateBook(ctx context.Context, bookID uint, userID uint, req dto.UpdateBookRequest) error {
 
 	book := &models.Book{
 		Title:      req.Title,
@@ -62,20 +118,114 @@ func (s *bookService) UpdateBook(bookID uint, userID uint, req dto.UpdateBookReq
 		TotalPages: req.TotalPages,
 	}
 
-	return s.repo.UpdateBook(bookID, userID, book)
+	// a renamed entry may now belong to a different work
+	if req.Title != "" || req.Author != "" {
+		current, err := s.repo.GetBookByID(ctx, bookID, userID)
+		if err != nil {
+			return err
+		}
+		linked := *current
+		if req.Title != "" {
+			linked.Title = req.Title
+		}
+		if req.Author != "" {
+			linked.Author = req.Author
+		}
+		if err := linkToCatalog(ctx, s.workRepo, &linked); err != nil {
+			return err
+		}
+		book.WorkID = linked.WorkID
+		book.EditionID = linked.EditionID
+	}
+
+	return s.repo.UpdateBook(ctx, bookID, userID, book)
 }
 
-func (s *bookService) DeleteBook(bookID uint, userID uint) error {
-	return s.repo.DeleteBook(bookID, userID)
+func (s *bookService) DeleteBook(ctx context.Context, bookID uint, userID uint) error {
+	err := s.repo.DeleteBook(ctx, bookID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBookNotFound
+	}
+	return err
 }
 
-func (s *bookService) GetSingleBook(bookID uint, userID uint) (*models.Book, error) {
-	return s.repo.GetBookByID(bookID, userID)
+func (s *bookService) GetSingleBook(ctx context.Context, bookID uint, userID uint) (*dto.BookDetail, error) {
+	book, err := s.repo.GetBookWithHistory(ctx, bookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &dto.BookDetail{Book: *book, ReadHistory: []dto.ReadThrough{}}
+	for _, read := range book.ReadHistory {
+		entry := dto.ReadThrough{
+			ID:          read.ID,
+			ReadNumber:  read.ReadNumber,
+			IsCurrent:   read.IsCurrent,
+			Status:      read.Status,
+			CurrentPage: read.CurrentPage,
+			StartedAt:   read.StartedAt,
+			FinishedAt:  read.FinishedAt,
+		}
+		if read.Review != nil {
+			entry.Rating = read.Review.Rating
+			entry.Review = read.Review.Comment
+		}
+		detail.ReadHistory = append(detail.ReadHistory, entry)
+	}
+	return detail, nil
 }
 
-func (s *bookService) GetDashboardStats(userID uint) (dto.DashboardStats, error) {
-	return s.repo.GetDashboardStats(userID)
+func (s *bookService) GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error) {
+	return s.repo.GetDashboardStats(ctx, userID)
+}
+func (s *bookService) SearchMyBooks(ctx context.Context, userID uint, query string) ([]models.Book, error) {
+	return s.repo.SearchBooks(ctx, userID, query)
 }
-func (s *bookService) SearchMyBooks(userID uint, query string) ([]models.Book, error) {
-	return s.repo.SearchBooks(userID, query)
+
+func (s *bookService) LookupISBN(ctx context.Context, isbn string) (*catalog.BookMetadata, error) {
+	normalized, err := utils.NormalizeISBN(isbn)
+	if err != nil || normalized == "" {
+		return nil, fmt.Errorf("%w: %q", utils.ErrInvalidISBN, isbn)
+	}
+	if s.catalog == nil {
+		return nil, ErrCatalogUnavailable
+	}
+	meta, err := s.catalog.LookupISBN(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	meta.ISBN = normalized
+	return meta, nil
+}
+
+func (s *bookService) LookupTitle(ctx context.Context, title string) ([]catalog.BookMetadata, error) {
+	if s.catalog == nil {
+		return nil, ErrCatalogUnavailable
+	}
+	return s.catalog.SearchTitle(ctx, title)
+}
+
+// needsAutoFill only asks the catalog when the book cannot be saved without
+// it, so a fully typed-in book never waits on a slow or unreachable catalog.
+func needsAutoFill(req dto.CreateBookRequest) bool {
+	return req.Title == "" || req.Author == ""
+}
+
+// fillFromCatalog only fills blanks; anything the user typed wins.
+func fillFromCatalog(req *dto.CreateBookRequest, meta *catalog.BookMetadata) {
+	if req.Title == "" {
+		req.Title = meta.Title
+	}
+	if req.Author == "" {
+		req.Author = meta.Author
+	}
+	if req.Genre == "" {
+		req.Genre = meta.Genre
+	}
+	if req.PublicationYear == 0 {
+		req.PublicationYear = meta.PublicationYear
+	}
+	if req.TotalPages == 0 {
+		req.TotalPages = meta.TotalPages
+	}
 }
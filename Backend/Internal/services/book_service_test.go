@@ -1,72 +1,120 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/catalog"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gorm.io/gorm"
 )
 
 // fake repo
 type FakeBookRepo struct {
-	Books []models.Book
-	Err   error
+	Books   []models.Book
+	History []models.ReadingProgress
+	Updated *models.Book
+	Err     error
 }
 
-func (f *FakeBookRepo) GetDashboardStats(userID uint) (dto.DashboardStats, error) {
+func (f *FakeBookRepo) GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error) {
 
 	return dto.DashboardStats{
 		TotalBooks: 5,
 	}, nil
 }
-func (f *FakeBookRepo) CreateBook(b *models.Book) error {
+func (f *FakeBookRepo) CreateBook(ctx context.Context, b *models.Book) error {
 	if f.Err != nil {
 		return f.Err
 	}
+	// same rule as the idx_user_title_author unique index
+	for _, existing := range f.Books {
+		if existing.UserID == b.UserID && existing.Title == b.Title && existing.Author == b.Author {
+			return repository.ErrDuplicateBook
+		}
+	}
 	f.Books = append(f.Books, *b)
 	return nil
 }
-func (f *FakeBookRepo) FindDuplicate(userID uint, title string, author string, isbn string) (*models.Book, error) {
+func (f *FakeBookRepo) FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error) {
 	return nil, nil
 }
-func (f *FakeBookRepo) SearchBooks(userID uint, query string) ([]models.Book, error) {
+func (f *FakeBookRepo) SearchBooks(ctx context.Context, userID uint, query string) ([]models.Book, error) {
 
 	return []models.Book{}, nil
 }
-func (f *FakeBookRepo) GetBooksByUserID(uid uint) ([]models.Book, error) {
+func (f *FakeBookRepo) GetBooksByUserID(ctx context.Context, uid uint) ([]models.Book, error) {
 	if f.Err != nil {
 		return nil, f.Err
 	}
 	return f.Books, nil
 }
 
-func (f *FakeBookRepo) GetBookByID(id uint, uid uint) (*models.Book, error) {
+func (f *FakeBookRepo) ListBooks(ctx context.Context, uid uint, q dto.BookListQuery) ([]models.Book, string, error) {
+	if f.Err != nil {
+		return nil, "", f.Err
+	}
+	return f.Books, "", nil
+}
+
+func (f *FakeBookRepo) GetBookByID(ctx context.Context, id uint, uid uint) (*models.Book, error) {
 	if f.Err != nil {
 		return nil, f.Err
 	}
+	for _, b := range f.Books {
+		if b.ID == id && b.UserID == uid {
+			return &b, nil
+		}
+	}
 	return &models.Book{ID: id, UserID: uid}, nil
 }
 
-func (f *FakeBookRepo) UpdateBook(bid uint, uid uint, b *models.Book) error {
+func (f *FakeBookRepo) GetBookWithHistory(ctx context.Context, id uint, uid uint) (*models.Book, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &models.Book{ID: id, UserID: uid, ReadHistory: f.History}, nil
+}
+
+func (f *FakeBookRepo) GetReadHistories(ctx context.Context, uid uint, bookIDs []uint) ([]models.ReadingProgress, error) {
+	var reads []models.ReadingProgress
+	for _, read := range f.History {
+		for _, id := range bookIDs {
+			if read.BookID == id {
+				reads = append(reads, read)
+			}
+		}
+	}
+	return reads, f.Err
+}
+
+func (f *FakeBookRepo) UpdateBook(ctx context.Context, bid uint, uid uint, b *models.Book) error {
+	f.Updated = b
 	return f.Err
 }
 
-func (f *FakeBookRepo) DeleteBook(bid uint, uid uint) error {
+func (f *FakeBookRepo) DeleteBook(ctx context.Context, bid uint, uid uint) error {
 	return f.Err
 }
 
 // create book
 
 func TestCreateBook_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
 	req := dto.CreateBookRequest{
 		Title:  "TDD Book",
 		Author: "Go Expert",
 	}
-	book, err := service.CreateBook(1, req)
+	book, err := service.CreateBook(ctx, 1, req)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -76,11 +124,25 @@ func TestCreateBook_Success(t *testing.T) {
 	}
 }
 
+func TestCreateBook_CountsOnlySavedBooks(t *testing.T) {
+	ctx := context.Background()
+	added := metrics.BooksAdded.WithLabelValues("manual")
+	before := testutil.ToFloat64(added)
+
+	NewBookService(&FakeBookRepo{}, &FakeWorkRepo{}, nil).CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Counted", Author: "A"})
+	NewBookService(&FakeBookRepo{Err: errors.New("db error")}, &FakeWorkRepo{}, nil).CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Lost", Author: "A"})
+
+	if got := testutil.ToFloat64(added) - before; got != 1 {
+		t.Errorf("Expected one book added to be counted, got %v", got)
+	}
+}
+
 func TestCreateBook_Failure(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{Err: errors.New("db error")}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	_, err := service.CreateBook(1, dto.CreateBookRequest{})
+	_, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "TDD Book", Author: "Go Expert"})
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -89,11 +151,12 @@ func TestCreateBook_Failure(t *testing.T) {
 // fetch book
 
 func TestFetchBooks_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{
 		Books: []models.Book{{Title: "Book 1"}, {Title: "Book 2"}},
 	}
-	service := NewBookService(repo)
-	books, err := service.FetchBooks(1)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+	books, err := service.FetchBooks(ctx, 1)
 
 	if err != nil {
 		t.Fatalf("Expected success, got error")
@@ -104,10 +167,11 @@ func TestFetchBooks_Success(t *testing.T) {
 }
 
 func TestFetchBooks_Failure(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{Err: errors.New("fetch error")}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	_, err := service.FetchBooks(1)
+	_, err := service.FetchBooks(ctx, 1)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -116,10 +180,11 @@ func TestFetchBooks_Failure(t *testing.T) {
 // getsingle book
 
 func TestGetSingleBook_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	book, err := service.GetSingleBook(1, 1)
+	book, err := service.GetSingleBook(ctx, 1, 1)
 	if err != nil {
 		t.Fatalf("Expected success, got error")
 	}
@@ -129,10 +194,11 @@ func TestGetSingleBook_Success(t *testing.T) {
 }
 
 func TestGetSingleBook_Failure(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{Err: errors.New("not found")}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	_, err := service.GetSingleBook(1, 1)
+	_, err := service.GetSingleBook(ctx, 1, 1)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -141,25 +207,52 @@ func TestGetSingleBook_Failure(t *testing.T) {
 // update book
 
 func TestUpdateBook_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
 	req := dto.UpdateBookRequest{
 		Title:  "Updated Title",
 		Author: "Updated Author",
 	}
 
-	err := service.UpdateBook(1, 1, req)
+	err := service.UpdateBook(ctx, 1, 1, req)
 	if err != nil {
 		t.Errorf("Expected success, got error: %v", err)
 	}
 }
 
+func TestUpdateBook_RenameMovesEditionWithWork(t *testing.T) {
+	ctx := context.Background()
+	oldWork, oldEdition := uint(1), uint(7)
+	repo := &FakeBookRepo{Books: []models.Book{
+		{ID: 1, UserID: 1, Title: "Old Title", Author: "Author", ISBN: "9780000000002", WorkID: &oldWork, EditionID: &oldEdition},
+	}}
+	works := &FakeWorkRepo{Works: []models.Work{{ID: oldWork, Key: WorkKey("Old Title", "Author")}}}
+	service := NewBookService(repo, works, nil)
+
+	if err := service.UpdateBook(ctx, 1, 1, dto.UpdateBookRequest{Title: "New Title"}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	updated := repo.Updated
+	if updated.WorkID == nil || *updated.WorkID == oldWork {
+		t.Fatalf("Expected the book to move to a new work, got %v", updated.WorkID)
+	}
+	if updated.EditionID == nil || *updated.EditionID == oldEdition {
+		t.Fatalf("Expected the edition to move with the work, got %v", updated.EditionID)
+	}
+	edition, _ := works.FindEditionByISBN(ctx, "9780000000002")
+	if edition.ID != *updated.EditionID || edition.WorkID != *updated.WorkID {
+		t.Errorf("Expected edition %d of work %d, got edition %d of work %d", edition.ID, edition.WorkID, *updated.EditionID, *updated.WorkID)
+	}
+}
+
 func TestUpdateBook_Failure(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{Err: errors.New("update failed")}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	err := service.UpdateBook(1, 1, dto.UpdateBookRequest{})
+	err := service.UpdateBook(ctx, 1, 1, dto.UpdateBookRequest{})
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -168,30 +261,43 @@ func TestUpdateBook_Failure(t *testing.T) {
 // delete book
 
 func TestDeleteBook_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	err := service.DeleteBook(1, 1)
+	err := service.DeleteBook(ctx, 1, 1)
 	if err != nil {
 		t.Errorf("Expected success, got error: %v", err)
 	}
 }
 
 func TestDeleteBook_Failure(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{Err: errors.New("delete failed")}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	err := service.DeleteBook(1, 1)
+	err := service.DeleteBook(ctx, 1, 1)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
 }
-func TestGetDashboardStats_Success(t *testing.T) {
 
+func TestDeleteBook_NotOwned(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{Err: gorm.ErrRecordNotFound}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	err := service.DeleteBook(ctx, 1, 2)
+	if !errors.Is(err, ErrBookNotFound) {
+		t.Errorf("Expected ErrBookNotFound, got %v", err)
+	}
+}
+func TestGetDashboardStats_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	stats, err := service.GetDashboardStats(1)
+	stats, err := service.GetDashboardStats(ctx, 1)
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
 	}
@@ -202,13 +308,184 @@ func TestGetDashboardStats_Success(t *testing.T) {
 
 }
 func TestSearchMyBooks_Success(t *testing.T) {
-
+	ctx := context.Background()
 	repo := &FakeBookRepo{}
-	service := NewBookService(repo)
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
 
-	_, err := service.SearchMyBooks(1, "Basheer")
+	_, err := service.SearchMyBooks(ctx, 1, "Basheer")
 
 	if err != nil {
 		t.Errorf("Expected search to work, but got error: %v", err)
 	}
 }
+
+func TestListBooks_Success(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{
+		Books: []models.Book{{Title: "Book 1"}, {Title: "Book 2"}},
+	}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	books, _, err := service.ListBooks(ctx, 1, dto.BookListQuery{Sort: "title", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if len(books) != 2 {
+		t.Errorf("Expected 2 books, got %d", len(books))
+	}
+}
+
+func TestListBooks_InvalidYearRange(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	_, _, err := service.ListBooks(ctx, 1, dto.BookListQuery{YearFrom: 2020, YearTo: 1990})
+	if !errors.Is(err, ErrInvalidYearRange) {
+		t.Errorf("Expected ErrInvalidYearRange, got %v", err)
+	}
+}
+
+func TestGetSingleBook_ReadHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{
+		History: []models.ReadingProgress{
+			{ID: 1, ReadNumber: 1, Status: "Finished", Review: &models.Review{Rating: 3}},
+			{ID: 2, ReadNumber: 2, IsCurrent: true, Status: "Finished", Review: &models.Review{Rating: 5}},
+		},
+	}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	book, err := service.GetSingleBook(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if len(book.ReadHistory) != 2 {
+		t.Fatalf("Expected 2 read-throughs, got %d", len(book.ReadHistory))
+	}
+	if book.ReadHistory[0].Rating != 3 || book.ReadHistory[1].Rating != 5 {
+		t.Errorf("Expected each read-through to carry its own rating, got %+v", book.ReadHistory)
+	}
+}
+
+var hobbitCatalog = catalog.NewFixtureProvider([]catalog.BookMetadata{
+	{ISBN: "9780618260300", Title: "The Hobbit", Author: "J.R.R. Tolkien", Genre: "Fantasy", PublicationYear: 1937, TotalPages: 366},
+})
+
+func TestCreateBook_AutoFillFromISBN(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, hobbitCatalog)
+
+	book, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{ISBN: "978-0-618-26030-0"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if book.Title != "The Hobbit" || book.Author != "J.R.R. Tolkien" || book.TotalPages != 366 || book.PublicationYear != 1937 {
+		t.Errorf("Expected catalog details, got %+v", book)
+	}
+}
+
+func TestCreateBook_AutoFillKeepsUserValues(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, hobbitCatalog)
+
+	req := dto.CreateBookRequest{ISBN: "9780618260300", Title: "Hobbit (annotated)", TotalPages: 400}
+	book, err := service.CreateBook(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if book.Title != "Hobbit (annotated)" || book.TotalPages != 400 || book.Author != "J.R.R. Tolkien" {
+		t.Errorf("Expected typed values to win, got %+v", book)
+	}
+}
+
+type FakeCatalog struct {
+	Lookups int
+}
+
+func (f *FakeCatalog) LookupISBN(ctx context.Context, isbn string) (*catalog.BookMetadata, error) {
+	f.Lookups++
+	return nil, errors.New("catalog unreachable")
+}
+
+func (f *FakeCatalog) SearchTitle(ctx context.Context, title string) ([]catalog.BookMetadata, error) {
+	return nil, errors.New("catalog unreachable")
+}
+
+func TestCreateBook_TitleAndAuthorSkipCatalog(t *testing.T) {
+	ctx := context.Background()
+	provider := &FakeCatalog{}
+	service := NewBookService(&FakeBookRepo{}, &FakeWorkRepo{}, provider)
+
+	req := dto.CreateBookRequest{ISBN: "9780618260300", Title: "The Hobbit", Author: "J.R.R. Tolkien"}
+	if _, err := service.CreateBook(ctx, 1, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if provider.Lookups != 0 {
+		t.Errorf("Expected no catalog lookup when title and author are given, got %d", provider.Lookups)
+	}
+}
+
+func TestCreateBook_UnknownISBN(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, hobbitCatalog)
+
+	_, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{ISBN: "9780134685991"})
+	if !errors.Is(err, ErrMissingBookDetails) {
+		t.Errorf("Expected ErrMissingBookDetails, got %v", err)
+	}
+}
+
+func TestLookupISBN_NoCatalog(t *testing.T) {
+	ctx := context.Background()
+	service := NewBookService(&FakeBookRepo{}, &FakeWorkRepo{}, nil)
+
+	_, err := service.LookupISBN(ctx, "9780618260300")
+	if !errors.Is(err, ErrCatalogUnavailable) {
+		t.Errorf("Expected ErrCatalogUnavailable, got %v", err)
+	}
+}
+
+func TestCreateBook_ISBN10StoredAsISBN13(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	book, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Effective Java", Author: "Joshua Bloch", ISBN: "0-13-468599-7"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if book.ISBN != "9780134685991" {
+		t.Errorf("Expected 9780134685991, got %s", book.ISBN)
+	}
+}
+
+func TestCreateBook_HyphenatedISBN13(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	book, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Effective Java", Author: "Joshua Bloch", ISBN: "978-0-13-468599-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if book.ISBN != "9780134685991" {
+		t.Errorf("Expected 9780134685991, got %s", book.ISBN)
+	}
+}
+
+func TestCreateBook_BadISBNChecksum(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeBookRepo{}
+	service := NewBookService(repo, &FakeWorkRepo{}, nil)
+
+	for _, isbn := range []string{"9780134685992", "0134685990", "12345"} {
+		_, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Effective Java", Author: "Joshua Bloch", ISBN: isbn})
+		if !errors.Is(err, utils.ErrInvalidISBN) {
+			t.Errorf("%s: expected ErrInvalidISBN, got %v", isbn, err)
+		}
+	}
+}
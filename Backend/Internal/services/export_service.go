@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+)
+
+const (
+	ExportFormatJSON      = "json"
+	ExportFormatCSV       = "csv"
+	ExportFormatGoodreads = "goodreads"
+
+	exportPageSize = 100
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported export format, use json, csv or goodreads")
+
+var exportCSVHeader = []string{
+	"id", "title", "author", "isbn", "genre", "publication_year", "total_pages", "added_at",
+	"status", "current_page", "started_at", "finished_at", "last_updated", "rating", "review", "reviewed_at",
+	"read_count",
+}
+
+var goodreadsCSVHeader = []string{
+	"Book Id", "Title", "Author", "Author l-f", "Additional Authors", "ISBN", "ISBN13",
+	"My Rating", "Average Rating", "Publisher", "Binding", "Number of Pages", "Year Published",
+	"Original Publication Year", "Date Read", "Date Added", "Bookshelves",
+	"Bookshelves with positions", "Exclusive Shelf", "My Review", "Spoiler", "Private Notes",
+	"Read Count", "Owned Copies",
+}
+
+type ExportService interface {
+	Export(ctx context.Context, userID uint, format string, w io.Writer) error
+}
+
+type exportService struct {
+	bookRepo   repository.BookRepository
+	reviewRepo repository.ReviewRepository
+	goalRepo   repository.GoalRepository
+}
+
+func NewExportService(bookRepo repository.BookRepository, reviewRepo repository.ReviewRepository, goalRepo repository.GoalRepository) ExportService {
+	return &exportService{
+		bookRepo:   bookRepo,
+		reviewRepo: reviewRepo,
+		goalRepo:   goalRepo,
+	}
+}
+
+func (s *exportService) Export(ctx context.Context, userID uint, format string, w io.Writer) error {
+	switch format {
+	case ExportFormatJSON:
+		return s.exportJSON(ctx, userID, w)
+	case ExportFormatCSV:
+		return s.exportCSV(ctx, userID, w, exportCSVHeader, exportCSVRow)
+	case ExportFormatGoodreads:
+		return s.exportCSV(ctx, userID, w, goodreadsCSVHeader, goodreadsCSVRow)
+	}
+	return ErrUnsupportedExportFormat
+}
+
+// forEachBook walks the library a page at a time so large libraries are
+// written out as they are read instead of being held in memory.
+func (s *exportService) forEachBook(ctx context.Context, userID uint, fn func(dto.ExportBook) error) error {
+	reviews, err := s.reviewRepo.GetReviewsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	index := exportReviews{byRead: map[uint]models.Review{}, byBook: map[uint]models.Review{}}
+	for _, r := range reviews {
+		// reviews from before re-reads are not tied to a read-through
+		if r.ReadingProgressID != nil {
+			index.byRead[*r.ReadingProgressID] = r
+		} else {
+			index.byBook[r.BookID] = r
+		}
+	}
+
+	query := dto.BookListQuery{Sort: "created_at", Order: "asc", Limit: exportPageSize}
+	for {
+		books, next, err := s.bookRepo.ListBooks(ctx, userID, query)
+		if err != nil {
+			return err
+		}
+		bookIDs := make([]uint, len(books))
+		for i, book := range books {
+			bookIDs[i] = book.ID
+		}
+		reads, err := s.bookRepo.GetReadHistories(ctx, userID, bookIDs)
+		if err != nil {
+			return err
+		}
+		history := make(map[uint][]models.ReadingProgress, len(books))
+		for _, read := range reads {
+			history[read.BookID] = append(history[read.BookID], read)
+		}
+
+		for _, book := range books {
+			if err := fn(toExportBook(book, history[book.ID], index)); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		query.Cursor = next
+	}
+}
+
+func (s *exportService) exportJSON(ctx context.Context, userID uint, w io.Writer) error {
+	exportedAt, _ := json.Marshal(time.Now().UTC())
+	if _, err := io.WriteString(w, `{"exported_at":`+string(exportedAt)+`,"books":[`); err != nil {
+		return err
+	}
+
+	first := true
+	err := s.forEachBook(ctx, userID, func(book dto.ExportBook) error {
+		data, err := json.Marshal(book)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	goals, err := s.goalRepo.GetGoalsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	exportGoals := make([]dto.ExportGoal, 0, len(goals))
+	for _, g := range goals {
+		exportGoals = append(exportGoals, dto.ExportGoal{
+			Type:        g.Type,
+			Metric:      g.Metric,
+			Year:        g.Year,
+			Month:       g.Month,
+			Week:        g.Week,
+			StartDate:   formatOptionalTime(g.StartDate, dateLayout),
+			EndDate:     formatOptionalTime(g.EndDate, dateLayout),
+			TargetBooks: g.TargetBooks,
+			TargetPages: g.TargetPages,
+		})
+	}
+	data, err := json.Marshal(exportGoals)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, `],"goals":`+string(data)+"}\n")
+	return err
+}
+
+func (s *exportService) exportCSV(ctx context.Context, userID uint, w io.Writer, header []string, row func(dto.ExportBook) []string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err := s.forEachBook(ctx, userID, func(book dto.ExportBook) error {
+		return writer.Write(row(book))
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type exportReviews struct {
+	byRead map[uint]models.Review
+	byBook map[uint]models.Review
+}
+
+// forRead finds the review of a read-through. A review not tied to any
+// read-through belongs to the book's current one.
+func (r exportReviews) forRead(bookID uint, read *models.ReadingProgress) (models.Review, bool) {
+	if read != nil {
+		if review, ok := r.byRead[read.ID]; ok {
+			return review, true
+		}
+		if !read.IsCurrent {
+			return models.Review{}, false
+		}
+	}
+	review, ok := r.byBook[bookID]
+	return review, ok
+}
+
+func toExportBook(book models.Book, history []models.ReadingProgress, reviews exportReviews) dto.ExportBook {
+	out := dto.ExportBook{
+		ID:              book.ID,
+		Title:           book.Title,
+		Author:          book.Author,
+		ISBN:            book.ISBN,
+		Genre:           book.Genre,
+		PublicationYear: book.PublicationYear,
+		TotalPages:      book.TotalPages,
+		AddedAt:         book.CreatedAt,
+		Status:          models.StatusWantToRead,
+		ReadHistory:     []dto.ReadThrough{},
+	}
+	if book.Progress != nil {
+		out.Status = book.Progress.Status
+		out.CurrentPage = book.Progress.CurrentPage
+		out.StartedAt = book.Progress.StartedAt
+		out.FinishedAt = book.Progress.FinishedAt
+		lastUpdated := book.Progress.LastUpdated
+		out.LastUpdated = &lastUpdated
+	}
+	if review, ok := reviews.forRead(book.ID, book.Progress); ok {
+		out.Rating = review.Rating
+		out.Review = review.Comment
+		reviewedAt := review.CreatedAt
+		out.ReviewedAt = &reviewedAt
+	}
+
+	for i := range history {
+		read := &history[i]
+		entry := dto.ReadThrough{
+			ID:          read.ID,
+			ReadNumber:  read.ReadNumber,
+			IsCurrent:   read.IsCurrent,
+			Status:      read.Status,
+			CurrentPage: read.CurrentPage,
+			StartedAt:   read.StartedAt,
+			FinishedAt:  read.FinishedAt,
+		}
+		if review, ok := reviews.forRead(book.ID, read); ok {
+			entry.Rating = review.Rating
+			entry.Review = review.Comment
+		}
+		if read.Status == models.StatusFinished {
+			out.ReadCount++
+		}
+		out.ReadHistory = append(out.ReadHistory, entry)
+	}
+	return out
+}
+
+func exportCSVRow(b dto.ExportBook) []string {
+	return []string{
+		strconv.FormatUint(uint64(b.ID), 10),
+		b.Title,
+		b.Author,
+		b.ISBN,
+		b.Genre,
+		formatOptionalInt(b.PublicationYear),
+		formatOptionalInt(b.TotalPages),
+		b.AddedAt.Format(time.RFC3339),
+		string(b.Status),
+		strconv.Itoa(b.CurrentPage),
+		formatOptionalTime(b.StartedAt, time.RFC3339),
+		formatOptionalTime(b.FinishedAt, time.RFC3339),
+		formatOptionalTime(b.LastUpdated, time.RFC3339),
+		formatOptionalInt(b.Rating),
+		b.Review,
+		formatOptionalTime(b.ReviewedAt, time.RFC3339),
+		strconv.Itoa(b.ReadCount),
+	}
+}
+
+func goodreadsCSVRow(b dto.ExportBook) []string {
+	shelf := goodreadsShelfFor(b.Status)
+	isbn10, isbn13 := "", ""
+	if len(b.ISBN) == 13 {
+		isbn13 = b.ISBN
+	} else {
+		isbn10 = b.ISBN
+	}
+
+	// Goodreads keeps one row per book: Date Read is the latest finish and
+	// Read Count how many times it was finished
+	dateRead, rating, review := "", b.Rating, b.Review
+	for _, read := range b.ReadHistory {
+		if read.Status == models.StatusFinished && read.FinishedAt != nil {
+			dateRead = read.FinishedAt.Format(goodreadsDateLayout)
+		}
+		// a re-read that is not reviewed yet keeps the last review
+		if b.Rating == 0 && read.Rating > 0 {
+			rating, review = read.Rating, read.Review
+		}
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(b.ID), 10),
+		b.Title,
+		b.Author,
+		"", "",
+		`="` + isbn10 + `"`,
+		`="` + isbn13 + `"`,
+		strconv.Itoa(rating),
+		"", "", "",
+		formatOptionalInt(b.TotalPages),
+		formatOptionalInt(b.PublicationYear),
+		formatOptionalInt(b.PublicationYear),
+		dateRead,
+		b.AddedAt.Format(goodreadsDateLayout),
+		shelf,
+		shelf + " (#1)",
+		shelf,
+		strings.ReplaceAll(review, "\n", "<br/>"),
+		"", "",
+		strconv.Itoa(b.ReadCount),
+		"0",
+	}
+}
+
+func goodreadsShelfFor(status models.ReadingStatus) string {
+	// Goodreads has no paused shelf, a paused book is still being read there
+	if status == models.StatusPaused {
+		return "currently-reading"
+	}
+	for shelf, s := range goodreadsShelves {
+		if s == status {
+			return shelf
+		}
+	}
+	return "to-read"
+}
+
+func formatOptionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func formatOptionalTime(t *time.Time, layout string) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+func TestExport_JSON(t *testing.T) {
+	ctx := context.Background()
+	finishedAt := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	finished := models.ReadingProgress{ID: 10, BookID: 1, ReadNumber: 1, IsCurrent: true, Status: "Finished", CurrentPage: 366, FinishedAt: &finishedAt, LastUpdated: time.Now()}
+	bookRepo := &FakeBookRepo{
+		Books: []models.Book{
+			{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", ISBN: "9780618260300", TotalPages: 366, Progress: &finished},
+			{ID: 2, Title: "Dune", Author: "Frank Herbert", TotalPages: 412},
+		},
+		History: []models.ReadingProgress{finished},
+	}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{BookID: 1, ReadingProgressID: &finished.ID, Rating: 5, Comment: "Loved it\nagain"}},
+	}
+	goalRepo := &FakeGoalRepo{Goal: &models.ReadingGoal{Year: 2025, Month: 3, TargetBooks: 2}}
+	service := NewExportService(bookRepo, reviewRepo, goalRepo)
+
+	var buf bytes.Buffer
+	if err := service.Export(ctx, 1, ExportFormatJSON, &buf); err != nil {
+		t.Fatalf("Expected export to succeed, but got error: %v", err)
+	}
+
+	var out struct {
+		Books []struct {
+			Title  string `json:"title"`
+			Status string `json:"status"`
+			Rating int    `json:"rating"`
+		} `json:"books"`
+		Goals []struct {
+			TargetBooks int `json:"target_books"`
+		} `json:"goals"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Expected valid JSON, but got error: %v", err)
+	}
+	if len(out.Books) != 2 || out.Books[0].Rating != 5 || out.Books[1].Status != "Want to Read" {
+		t.Errorf("Unexpected exported books: %+v", out.Books)
+	}
+	if len(out.Goals) != 1 || out.Goals[0].TargetBooks != 2 {
+		t.Errorf("Unexpected exported goals: %+v", out.Goals)
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	ctx := context.Background()
+	finishedAt := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	finished := models.ReadingProgress{ID: 10, BookID: 1, ReadNumber: 1, IsCurrent: true, Status: "Finished", CurrentPage: 366, FinishedAt: &finishedAt, LastUpdated: time.Now()}
+	bookRepo := &FakeBookRepo{
+		Books: []models.Book{
+			{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", ISBN: "9780618260300", TotalPages: 366, Progress: &finished},
+			{ID: 2, Title: "Dune", Author: "Frank Herbert", TotalPages: 412},
+		},
+		History: []models.ReadingProgress{finished},
+	}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{BookID: 1, ReadingProgressID: &finished.ID, Rating: 5, Comment: "Loved it\nagain"}},
+	}
+	service := NewExportService(bookRepo, reviewRepo, &FakeGoalRepo{})
+
+	var buf bytes.Buffer
+	if err := service.Export(ctx, 1, ExportFormatCSV, &buf); err != nil {
+		t.Fatalf("Expected export to succeed, but got error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, but got error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("Expected header and 2 rows, got %d records", len(records))
+	}
+}
+
+func TestExport_GoodreadsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	finishedAt := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	finished := models.ReadingProgress{ID: 10, BookID: 1, ReadNumber: 1, IsCurrent: true, Status: "Finished", CurrentPage: 366, FinishedAt: &finishedAt, LastUpdated: time.Now()}
+	bookRepo := &FakeBookRepo{
+		Books: []models.Book{
+			{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", ISBN: "9780618260300", TotalPages: 366, Progress: &finished},
+			{ID: 2, Title: "Dune", Author: "Frank Herbert", TotalPages: 412},
+		},
+		History: []models.ReadingProgress{finished},
+	}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{BookID: 1, ReadingProgressID: &finished.ID, Rating: 5, Comment: "Loved it\nagain"}},
+	}
+	service := NewExportService(bookRepo, reviewRepo, &FakeGoalRepo{})
+
+	var buf bytes.Buffer
+	if err := service.Export(ctx, 1, ExportFormatGoodreads, &buf); err != nil {
+		t.Fatalf("Expected export to succeed, but got error: %v", err)
+	}
+	exported := buf.String()
+
+	importRepo := &FakeBookRepoForImport{}
+	importReviews := &FakeReviewRepo{}
+	report, err := NewImportService(importRepo, &FakeProgressRepo{}, importReviews, &FakeWorkRepo{}, &FakeUserRepo{}).ImportGoodreads(ctx, 2, &buf, "")
+	if err != nil {
+		t.Fatalf("Expected Goodreads export to import cleanly, but got error: %v", err)
+	}
+	if report.Created != 2 {
+		t.Errorf("Expected 2 books re-imported, got %d", report.Created)
+	}
+	if !strings.Contains(exported, "2025/03/14") {
+		t.Errorf("Expected Date Read to come from the finish date")
+	}
+	if importRepo.Books[0].ISBN != "9780618260300" {
+		t.Errorf("Expected ISBN to survive the round trip, got %q", importRepo.Books[0].ISBN)
+	}
+	if len(importReviews.Reviews) != 1 || importReviews.Reviews[0].Comment != "Loved it\nagain" {
+		t.Errorf("Expected review to survive the round trip, got %+v", importReviews.Reviews)
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	ctx := context.Background()
+	service := NewExportService(&FakeBookRepo{}, &FakeReviewRepo{}, &FakeGoalRepo{})
+
+	err := service.Export(ctx, 1, "xml", &bytes.Buffer{})
+	if !errors.Is(err, ErrUnsupportedExportFormat) {
+		t.Errorf("Expected ErrUnsupportedExportFormat, got %v", err)
+	}
+}
+
+func TestExport_IncludesEveryReadThrough(t *testing.T) {
+	ctx := context.Background()
+	firstFinish := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	secondFinish := time.Date(2024, 8, 9, 0, 0, 0, 0, time.UTC)
+	first := models.ReadingProgress{ID: 10, BookID: 1, ReadNumber: 1, Status: "Finished", CurrentPage: 366, FinishedAt: &firstFinish}
+	second := models.ReadingProgress{ID: 11, BookID: 1, ReadNumber: 2, Status: "Finished", CurrentPage: 366, FinishedAt: &secondFinish}
+	third := models.ReadingProgress{ID: 12, BookID: 1, ReadNumber: 3, IsCurrent: true, Status: "Currently Reading", CurrentPage: 40}
+	bookRepo := &FakeBookRepo{
+		Books:   []models.Book{{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", TotalPages: 366, Progress: &third}},
+		History: []models.ReadingProgress{first, second, third},
+	}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{
+			{BookID: 1, ReadingProgressID: &first.ID, Rating: 3, Comment: "Slow start"},
+			{BookID: 1, ReadingProgressID: &second.ID, Rating: 5, Comment: "Better the second time"},
+		},
+	}
+	service := NewExportService(bookRepo, reviewRepo, &FakeGoalRepo{})
+
+	var buf bytes.Buffer
+	if err := service.Export(ctx, 1, ExportFormatJSON, &buf); err != nil {
+		t.Fatalf("Expected export to succeed, but got error: %v", err)
+	}
+	var out struct {
+		Books []dto.ExportBook `json:"books"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Expected valid JSON, but got error: %v", err)
+	}
+	book := out.Books[0]
+	if book.ReadCount != 2 || len(book.ReadHistory) != 3 {
+		t.Fatalf("Expected 2 finished reads out of 3, got %d / %+v", book.ReadCount, book.ReadHistory)
+	}
+	if book.ReadHistory[0].Rating != 3 || book.ReadHistory[1].Rating != 5 || book.ReadHistory[2].Rating != 0 {
+		t.Errorf("Expected each read-through to keep its own review, got %+v", book.ReadHistory)
+	}
+	if book.Status != "Currently Reading" || book.Rating != 0 {
+		t.Errorf("Expected the top-level fields to describe the current read, got %+v", book)
+	}
+
+	buf.Reset()
+	if err := service.Export(ctx, 1, ExportFormatGoodreads, &buf); err != nil {
+		t.Fatalf("Expected export to succeed, but got error: %v", err)
+	}
+	records, _ := csv.NewReader(&buf).ReadAll()
+	row := records[1]
+	if row[14] != "2024/08/09" || row[22] != "2" {
+		t.Errorf("Expected the latest Date Read and a Read Count of 2, got %q and %q", row[14], row[22])
+	}
+	if row[7] != "5" || row[19] != "Better the second time" {
+		t.Errorf("Expected the latest review while the re-read has none, got %q / %q", row[7], row[19])
+	}
+}
@@ -1,14 +1,24 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 )
 
+const dateLayout = "2006-01-02"
+
+var ErrInvalidGoal = errors.New("invalid goal")
+
 type GoalService interface {
-	SetUserGoal(userID uint, req dto.SetGoalRequest) error
-	GetProgress(userID uint, year int, month int) (*dto.GoalProgressResponse, error)
+	SetUserGoal(ctx context.Context, userID uint, req dto.SetGoalRequest) error
+	GetProgress(ctx context.Context, userID uint, year int, month int) (*dto.GoalProgressResponse, error)
+	ListGoals(ctx context.Context, userID uint) ([]dto.GoalProgressResponse, error)
 }
 
 type goalService struct {
@@ -19,32 +29,157 @@ func NewGoalService(repo repository.GoalRepository) GoalService {
 	return &goalService{repo: repo}
 }
 
-func (s *goalService) SetUserGoal(userID uint, req dto.SetGoalRequest) error {
-	goal := &models.ReadingGoal{
-		UserID:      userID,
-		Year:        req.Year,
-		Month:       req.Month,
-		TargetBooks: req.TargetBooks,
+func (s *goalService) SetUserGoal(ctx context.Context, userID uint, req dto.SetGoalRequest) error {
+	goal, err := buildGoal(req)
+	if err != nil {
+		return err
 	}
-	return s.repo.SaveGoal(goal)
+	goal.UserID = userID
+	return s.repo.SaveGoal(ctx, goal)
 }
 
-func (s *goalService) GetProgress(userID uint, year int, month int) (*dto.GoalProgressResponse, error) {
+func (s *goalService) GetProgress(ctx context.Context, userID uint, year int, month int) (*dto.GoalProgressResponse, error) {
 
-	goal, err := s.repo.GetGoal(userID, year, month)
+	goal, err := s.repo.GetGoal(ctx, userID, year, month)
 	if err != nil {
 		return nil, err
 	}
 
-	finishedCount, err := s.repo.CountFinishedBooks(userID, year, month)
+	return s.progressFor(ctx, userID, goal)
+}
+
+func (s *goalService) ListGoals(ctx context.Context, userID uint) ([]dto.GoalProgressResponse, error) {
+	goals, err := s.repo.GetGoalsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.GoalProgressResponse, 0, len(goals))
+	for i := range goals {
+		progress, err := s.progressFor(ctx, userID, &goals[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *progress)
+	}
+	return result, nil
+}
+
+func (s *goalService) progressFor(ctx context.Context, userID uint, goal *models.ReadingGoal) (*dto.GoalProgressResponse, error) {
+	start, end := goalPeriod(goal)
+
+	var current int64
+	var err error
+	target := goal.TargetBooks
+	if goal.Metric == models.GoalMetricPages {
+		target = goal.TargetPages
+		current, err = s.repo.SumPagesReadBetween(ctx, userID, start, end)
+	} else {
+		current, err = s.repo.CountFinishedBooksBetween(ctx, userID, start, end)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.GoalProgressResponse{
+		ID:          goal.ID,
+		Type:        goal.Type,
+		Metric:      goal.Metric,
 		Year:        goal.Year,
-		Target:      goal.TargetBooks,
-		Current:     int(finishedCount),
-		IsCompleted: int(finishedCount) >= goal.TargetBooks,
+		Month:       goal.Month,
+		Week:        goal.Week,
+		StartDate:   start.Format(dateLayout),
+		EndDate:     end.AddDate(0, 0, -1).Format(dateLayout),
+		Target:      target,
+		Current:     int(current),
+		IsCompleted: int(current) >= target,
 	}, nil
 }
+
+// buildGoal validates the request for its goal type and works out the
+// period the goal covers.
+func buildGoal(req dto.SetGoalRequest) (*models.ReadingGoal, error) {
+	goal := &models.ReadingGoal{
+		Type:        req.Type,
+		Metric:      req.Metric,
+		Year:        req.Year,
+		TargetBooks: req.TargetBooks,
+		TargetPages: req.TargetPages,
+	}
+	if goal.Type == "" {
+		goal.Type = models.GoalTypeMonthly
+	}
+	if goal.Metric == "" {
+		goal.Metric = models.GoalMetricBooks
+	}
+
+	if goal.Metric == models.GoalMetricPages && goal.TargetPages < 1 {
+		return nil, fmt.Errorf("%w: target_pages is required for a pages goal", ErrInvalidGoal)
+	}
+	if goal.Metric == models.GoalMetricBooks && goal.TargetBooks < 1 {
+		return nil, fmt.Errorf("%w: target_books is required for a books goal", ErrInvalidGoal)
+	}
+
+	var start, end time.Time
+	switch goal.Type {
+	case models.GoalTypeMonthly:
+		if req.Year == 0 || req.Month == 0 {
+			return nil, fmt.Errorf("%w: year and month are required for a monthly goal", ErrInvalidGoal)
+		}
+		goal.Month = req.Month
+		start = time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, -1)
+	case models.GoalTypeYearly:
+		if req.Year == 0 {
+			return nil, fmt.Errorf("%w: year is required for a yearly goal", ErrInvalidGoal)
+		}
+		start = time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(1, 0, -1)
+	case models.GoalTypeWeekly:
+		if req.Year == 0 || req.Week == 0 {
+			return nil, fmt.Errorf("%w: year and week are required for a weekly goal", ErrInvalidGoal)
+		}
+		start = isoWeekStart(req.Year, req.Week)
+		if y, w := start.ISOWeek(); y != req.Year || w != req.Week {
+			return nil, fmt.Errorf("%w: that year does not have the requested week", ErrInvalidGoal)
+		}
+		goal.Week = req.Week
+		end = start.AddDate(0, 0, 6)
+	case models.GoalTypeCustom:
+		var err error
+		start, err = time.Parse(dateLayout, req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: start_date must be a date like 2026-01-31", ErrInvalidGoal)
+		}
+		end, err = time.Parse(dateLayout, req.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: end_date must be a date like 2026-01-31", ErrInvalidGoal)
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("%w: end_date cannot be before start_date", ErrInvalidGoal)
+		}
+		goal.Year = start.Year()
+	default:
+		return nil, fmt.Errorf("%w: unknown goal type", ErrInvalidGoal)
+	}
+
+	goal.StartDate = &start
+	goal.EndDate = &end
+	return goal, nil
+}
+
+// goalPeriod returns the goal's period as [start, end) for querying.
+func goalPeriod(goal *models.ReadingGoal) (time.Time, time.Time) {
+	if goal.StartDate != nil && goal.EndDate != nil {
+		return *goal.StartDate, goal.EndDate.AddDate(0, 0, 1)
+	}
+	start := time.Date(goal.Year, time.Month(goal.Month), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// isoWeekStart returns the Monday of the given ISO week.
+func isoWeekStart(year int, week int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
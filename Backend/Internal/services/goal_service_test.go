@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
@@ -11,43 +13,58 @@ import (
 type FakeGoalRepo struct {
 	Goal       *models.ReadingGoal
 	Count      int64
+	Pages      int64
+	From, To   time.Time
 	Err        error
 	SaveCalled bool
 }
 
-func (f *FakeGoalRepo) SaveGoal(goal *models.ReadingGoal) error {
+func (f *FakeGoalRepo) SaveGoal(ctx context.Context, goal *models.ReadingGoal) error {
 	f.SaveCalled = true
 	f.Goal = goal
 	return f.Err
 }
 
-func (f *FakeGoalRepo) GetGoal(userID uint, year int, month int) (*models.ReadingGoal, error) {
+func (f *FakeGoalRepo) GetGoal(ctx context.Context, userID uint, year int, month int) (*models.ReadingGoal, error) {
 	if f.Err != nil {
 		return nil, f.Err
 	}
 	return f.Goal, nil
 }
 
-func (f *FakeGoalRepo) CountFinishedBooks(userID uint, year int, month int) (int64, error) {
+func (f *FakeGoalRepo) CountFinishedBooksBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
+	f.From, f.To = start, end
 	return f.Count, nil
 }
 
-func (f *FakeGoalRepo) GetYearlyTotalTarget(userID uint, year int) (int, error) {
+func (f *FakeGoalRepo) SumPagesReadBetween(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
+	f.From, f.To = start, end
+	return f.Pages, nil
+}
+
+func (f *FakeGoalRepo) GetYearlyTotalTarget(ctx context.Context, userID uint, year int) (int, error) {
 	if f.Goal != nil {
 		return f.Goal.TargetBooks, nil
 	}
 	return 0, f.Err
 }
 
-func TestGetGoalProgress_NotCompleted(t *testing.T) {
+func (f *FakeGoalRepo) GetGoalsByUserID(ctx context.Context, userID uint) ([]models.ReadingGoal, error) {
+	if f.Goal != nil {
+		return []models.ReadingGoal{*f.Goal}, nil
+	}
+	return nil, f.Err
+}
 
+func TestGetGoalProgress_NotCompleted(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeGoalRepo{
 		Goal:  &models.ReadingGoal{Year: 2026, Month: 1, TargetBooks: 5},
 		Count: 2,
 	}
 	goalService := NewGoalService(repo)
 
-	result, err := goalService.GetProgress(1, 2026, 1)
+	result, err := goalService.GetProgress(ctx, 1, 2026, 1)
 
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
@@ -58,14 +75,14 @@ func TestGetGoalProgress_NotCompleted(t *testing.T) {
 }
 
 func TestGetGoalProgress_Completed(t *testing.T) {
-
+	ctx := context.Background()
 	repo := &FakeGoalRepo{
 		Goal:  &models.ReadingGoal{Year: 2026, Month: 1, TargetBooks: 3},
 		Count: 3,
 	}
 	goalService := NewGoalService(repo)
 
-	result, err := goalService.GetProgress(1, 2026, 1)
+	result, err := goalService.GetProgress(ctx, 1, 2026, 1)
 
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
@@ -76,12 +93,13 @@ func TestGetGoalProgress_Completed(t *testing.T) {
 }
 
 func TestGetGoalProgress_NotFound(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeGoalRepo{
 		Err: errors.New("record not found"),
 	}
 	goalService := NewGoalService(repo)
 
-	_, err := goalService.GetProgress(1, 2030, 1)
+	_, err := goalService.GetProgress(ctx, 1, 2030, 1)
 
 	if err == nil {
 		t.Errorf("Expected an error for a missing goal, but got nil")
@@ -89,6 +107,7 @@ func TestGetGoalProgress_NotFound(t *testing.T) {
 }
 
 func TestSetUserGoal_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeGoalRepo{}
 	goalService := NewGoalService(repo)
 
@@ -98,7 +117,7 @@ func TestSetUserGoal_Success(t *testing.T) {
 		TargetBooks: 10,
 	}
 
-	err := goalService.SetUserGoal(1, req)
+	err := goalService.SetUserGoal(ctx, 1, req)
 
 	if err != nil {
 		t.Errorf("Expected no error, but got %v", err)
@@ -114,14 +133,94 @@ func TestSetUserGoal_Success(t *testing.T) {
 }
 
 func TestGetProgress_RepoError(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeGoalRepo{
 		Err: errors.New("database connection failed"),
 	}
 	goalService := NewGoalService(repo)
 
-	_, err := goalService.GetProgress(1, 2026, 1)
+	_, err := goalService.GetProgress(ctx, 1, 2026, 1)
 
 	if err == nil {
 		t.Errorf("Expected error from repository, but got nil")
 	}
 }
+
+func TestSetUserGoal_Yearly(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeGoalRepo{}
+	goalService := NewGoalService(repo)
+
+	err := goalService.SetUserGoal(ctx, 1, dto.SetGoalRequest{Type: "yearly", Year: 2026, TargetBooks: 52})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if repo.Goal.StartDate.Format("2006-01-02") != "2026-01-01" || repo.Goal.EndDate.Format("2006-01-02") != "2026-12-31" {
+		t.Errorf("Expected the goal to cover 2026, got %v to %v", repo.Goal.StartDate, repo.Goal.EndDate)
+	}
+}
+
+func TestSetUserGoal_WeeklyUsesISOWeek(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeGoalRepo{}
+	goalService := NewGoalService(repo)
+
+	err := goalService.SetUserGoal(ctx, 1, dto.SetGoalRequest{Type: "weekly", Year: 2026, Week: 1, TargetPages: 200, Metric: "pages"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	// ISO week 1 of 2026 starts on Monday 29 December 2025
+	if repo.Goal.StartDate.Format("2006-01-02") != "2025-12-29" {
+		t.Errorf("Expected week to start 2025-12-29, got %v", repo.Goal.StartDate)
+	}
+}
+
+func TestSetUserGoal_CustomRangeInvalid(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeGoalRepo{}
+	goalService := NewGoalService(repo)
+
+	err := goalService.SetUserGoal(ctx, 1, dto.SetGoalRequest{Type: "custom", StartDate: "2026-06-30", EndDate: "2026-06-01", TargetBooks: 3})
+	if !errors.Is(err, ErrInvalidGoal) {
+		t.Errorf("Expected ErrInvalidGoal for a reversed range, got %v", err)
+	}
+	if repo.SaveCalled {
+		t.Errorf("Expected an invalid goal not to be saved")
+	}
+}
+
+func TestSetUserGoal_PagesGoalNeedsTargetPages(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeGoalRepo{}
+	goalService := NewGoalService(repo)
+
+	err := goalService.SetUserGoal(ctx, 1, dto.SetGoalRequest{Type: "yearly", Metric: "pages", Year: 2026, TargetBooks: 10})
+	if !errors.Is(err, ErrInvalidGoal) {
+		t.Errorf("Expected ErrInvalidGoal, got %v", err)
+	}
+}
+
+func TestListGoals_PagesProgress(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	repo := &FakeGoalRepo{
+		Goal: &models.ReadingGoal{
+			Type: "yearly", Metric: "pages", Year: 2026, TargetPages: 5000,
+			StartDate: &start, EndDate: &end,
+		},
+		Pages: 5200,
+	}
+	goalService := NewGoalService(repo)
+
+	goals, err := goalService.ListGoals(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if len(goals) != 1 || !goals[0].IsCompleted || goals[0].Current != 5200 {
+		t.Errorf("Expected the pages goal to be completed, got %+v", goals)
+	}
+	if !repo.To.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the query to include the last day of the year, got end %v", repo.To)
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+)
+
+const (
+	ImportModeSkip  = "skip"
+	ImportModeMerge = "merge"
+
+	goodreadsDateLayout = "2006/01/02"
+)
+
+// Goodreads exclusive shelves and the progress status each one maps to.
+var goodreadsShelves = map[string]models.ReadingStatus{
+	"read":              models.StatusFinished,
+	"currently-reading": models.StatusReading,
+	"to-read":           models.StatusWantToRead,
+	"did-not-finish":    models.StatusDidNotFinish,
+}
+
+type ImportService interface {
+	ImportGoodreads(ctx context.Context, userID uint, file io.Reader, mode string) (*dto.ImportReport, error)
+}
+
+type importService struct {
+	bookRepo     repository.BookRepository
+	progressRepo repository.ProgressRepository
+	reviewRepo   repository.ReviewRepository
+	workRepo     repository.WorkRepository
+	userRepo     repository.UserRepository
+}
+
+func NewImportService(bookRepo repository.BookRepository, progressRepo repository.ProgressRepository, reviewRepo repository.ReviewRepository, workRepo repository.WorkRepository, userRepo repository.UserRepository) ImportService {
+	return &importService{
+		bookRepo:     bookRepo,
+		progressRepo: progressRepo,
+		reviewRepo:   reviewRepo,
+		workRepo:     workRepo,
+		userRepo:     userRepo,
+	}
+}
+
+type goodreadsRow struct {
+	title   string
+	author  string
+	isbn    string
+	pages   int
+	year    int
+	rating  int
+	review  string
+	readAt  *time.Time
+	status  models.ReadingStatus
+	lineNum int
+	// note tells the user about anything guessed while reading the row
+	note string
+}
+
+func (s *importService) ImportGoodreads(ctx context.Context, userID uint, file io.Reader, mode string) (*dto.ImportReport, error) {
+	if mode == "" {
+		mode = ImportModeSkip
+	}
+	if mode != ImportModeSkip && mode != ImportModeMerge {
+		return nil, fmt.Errorf("unknown import mode %q, use skip or merge", mode)
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("could not read CSV header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := columns["Title"]; !ok {
+		return nil, errors.New("this does not look like a Goodreads export: missing Title column")
+	}
+	if _, ok := columns["Author"]; !ok {
+		return nil, errors.New("this does not look like a Goodreads export: missing Author column")
+	}
+
+	// Goodreads has no visibility, so imported reviews get the user's default
+	visibility := defaultReviewVisibility(ctx, s.userRepo, userID)
+
+	report := &dto.ImportReport{Rows: []dto.ImportRowResult{}}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			addImportResult(report, dto.ImportRowResult{Row: line, Action: "failed", Message: "malformed CSV row"})
+			continue
+		}
+
+		row := parseGoodreadsRow(record, columns)
+		row.lineNum = line
+		addImportResult(report, s.importRow(ctx, userID, row, mode, visibility))
+	}
+
+	logging.FromContext(ctx).Info("goodreads import finished", "mode", mode,
+		"created", report.Created, "merged", report.Merged, "skipped", report.Skipped, "failed", report.Failed)
+	return report, nil
+}
+
+func (s *importService) importRow(ctx context.Context, userID uint, row goodreadsRow, mode string, visibility models.ReviewVisibility) dto.ImportRowResult {
+	result := dto.ImportRowResult{Row: row.lineNum, Title: row.title, Author: row.author}
+
+	if row.title == "" || row.author == "" {
+		result.Action = "failed"
+		result.Message = "title and author are required"
+		return result
+	}
+
+	existing, _ := s.bookRepo.FindDuplicate(ctx, userID, row.title, row.author, row.isbn)
+	if existing != nil {
+		result.BookID = existing.ID
+		if mode == ImportModeSkip {
+			result.Action = "skipped"
+			result.Message = "already in your library"
+			return result
+		}
+		if err := s.mergeBook(ctx, existing, row, visibility); err != nil {
+			result.Action = "failed"
+			result.Message = err.Error()
+			return result
+		}
+		result.Action = "merged"
+		result.Message = row.note
+		return result
+	}
+
+	book := &models.Book{
+		UserID:          userID,
+		Title:           row.title,
+		Author:          row.author,
+		ISBN:            row.isbn,
+		PublicationYear: row.year,
+		TotalPages:      row.pages,
+	}
+	if err := linkToCatalog(ctx, s.workRepo, book); err != nil {
+		result.Action = "failed"
+		result.Message = err.Error()
+		return result
+	}
+	if err := s.bookRepo.CreateBook(ctx, book); err != nil {
+		result.Action = "failed"
+		result.Message = err.Error()
+		return result
+	}
+	metrics.BooksAdded.WithLabelValues("import").Inc()
+	result.BookID = book.ID
+	result.Action = "created"
+	result.Message = row.note
+
+	progress := progressForRow(book, row)
+	if err := s.progressRepo.Save(ctx, progress); err != nil {
+		result.Message = "book added but reading status could not be saved"
+		return result
+	}
+	if row.rating > 0 {
+		if err := s.reviewRepo.CreateReview(ctx, reviewForRow(book, progress, row, visibility)); err != nil {
+			result.Message = "book added but review could not be saved"
+		}
+	}
+	return result
+}
+
+// mergeBook only fills in what the library entry is missing; it never
+// overwrites data the user already entered or moves a status backwards.
+func (s *importService) mergeBook(ctx context.Context, existing *models.Book, row goodreadsRow, visibility models.ReviewVisibility) error {
+	patch := models.Book{}
+	if existing.ISBN == "" && row.isbn != "" {
+		patch.ISBN = row.isbn
+		linked := *existing
+		linked.ISBN = row.isbn
+		if err := linkToCatalog(ctx, s.workRepo, &linked); err != nil {
+			return err
+		}
+		patch.WorkID = linked.WorkID
+		patch.EditionID = linked.EditionID
+	}
+	if existing.TotalPages == 0 {
+		patch.TotalPages = row.pages
+	}
+	if existing.PublicationYear == 0 {
+		patch.PublicationYear = row.year
+	}
+	if patch.ISBN != "" || patch.TotalPages != 0 || patch.PublicationYear != 0 {
+		if err := s.bookRepo.UpdateBook(ctx, existing.ID, existing.UserID, &patch); err != nil {
+			return err
+		}
+		if patch.TotalPages != 0 {
+			existing.TotalPages = patch.TotalPages
+		}
+	}
+
+	progress, err := s.progressRepo.GetByBookID(ctx, existing.ID)
+	if err != nil {
+		progress = nil
+	}
+	if progress == nil || statusRank(row.status) > statusRank(progress.Status) {
+		imported := progressForRow(existing, row)
+		if progress != nil {
+			imported.ID = progress.ID
+			imported.ReadNumber = progress.ReadNumber
+			imported.StartedAt = progress.StartedAt
+		}
+		if err := s.progressRepo.Save(ctx, imported); err != nil {
+			return err
+		}
+		progress = imported
+	}
+
+	if row.rating > 0 {
+		if review, _ := s.reviewRepo.GetReviewByBookID(ctx, existing.ID); review == nil {
+			if err := s.reviewRepo.CreateReview(ctx, reviewForRow(existing, progress, row, visibility)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func progressForRow(book *models.Book, row goodreadsRow) *models.ReadingProgress {
+	progress := &models.ReadingProgress{BookID: book.ID, ReadNumber: 1, IsCurrent: true, Status: row.status}
+	if row.status == models.StatusFinished {
+		progress.CurrentPage = book.TotalPages
+		progress.FinishedAt = row.readAt
+	}
+	return progress
+}
+
+func reviewForRow(book *models.Book, progress *models.ReadingProgress, row goodreadsRow, visibility models.ReviewVisibility) *models.Review {
+	rating := row.rating
+	if rating > 5 {
+		rating = 5
+	}
+	return &models.Review{
+		BookID:            book.ID,
+		UserID:            book.UserID,
+		ReadingProgressID: &progress.ID,
+		Rating:            rating,
+		Comment:           row.review,
+		Visibility:        visibility,
+	}
+}
+
+// statusRank orders statuses by how far along they are, so merges never move backwards.
+func statusRank(status models.ReadingStatus) int {
+	switch status {
+	case models.StatusFinished, models.StatusDidNotFinish:
+		return 2
+	case models.StatusReading, models.StatusPaused:
+		return 1
+	}
+	return 0
+}
+
+func addImportResult(report *dto.ImportReport, result dto.ImportRowResult) {
+	switch result.Action {
+	case "created":
+		report.Created++
+	case "merged":
+		report.Merged++
+	case "skipped":
+		report.Skipped++
+	default:
+		report.Failed++
+	}
+	report.Rows = append(report.Rows, result)
+}
+
+func parseGoodreadsRow(record []string, columns map[string]int) goodreadsRow {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := goodreadsRow{
+		title:  get("Title"),
+		author: get("Author"),
+		review: strings.ReplaceAll(get("My Review"), "<br/>", "\n"),
+	}
+
+	// Goodreads wraps ISBNs as ="0439023483" to stop spreadsheets mangling them
+	row.isbn = cleanGoodreadsISBN(get("ISBN13"))
+	if row.isbn == "" {
+		row.isbn = cleanGoodreadsISBN(get("ISBN"))
+	}
+	// a broken ISBN should not cost the user the whole book, import it without one
+	if isbn, err := utils.NormalizeISBN(row.isbn); err == nil {
+		row.isbn = isbn
+	} else {
+		row.isbn = ""
+	}
+
+	row.pages, _ = strconv.Atoi(get("Number of Pages"))
+	row.year, _ = strconv.Atoi(get("Original Publication Year"))
+	if row.year == 0 {
+		row.year, _ = strconv.Atoi(get("Year Published"))
+	}
+	row.rating, _ = strconv.Atoi(get("My Rating"))
+
+	row.status = goodreadsShelves[get("Exclusive Shelf")]
+	if row.status == "" {
+		row.status = models.StatusWantToRead
+	}
+
+	if readAt, err := time.Parse(goodreadsDateLayout, get("Date Read")); err == nil {
+		row.readAt = &readAt
+	} else if row.status == models.StatusFinished {
+		// Goodreads often leaves Date Read blank on older reads; without a
+		// finish date the book would never count towards goals or stats
+		if addedAt, err := time.Parse(goodreadsDateLayout, get("Date Added")); err == nil {
+			row.readAt = &addedAt
+			row.note = "no Date Read, finish date taken from Date Added"
+		} else {
+			row.note = "no Date Read, this read has no finish date"
+		}
+	}
+	return row
+}
+
+func cleanGoodreadsISBN(value string) string {
+	value = strings.TrimPrefix(value, "=")
+	return strings.Trim(value, "\" ")
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+const goodreadsCSV = `Book Id,Title,Author,ISBN,ISBN13,My Rating,Number of Pages,Year Published,Original Publication Year,Exclusive Shelf,My Review
+1,The Hobbit,J.R.R. Tolkien,"=""0618260307""","=""9780618260300""",5,366,2002,1937,read,Loved it<br/>again
+2,Dune,Frank Herbert,"=""""","=""""",0,412,1990,1965,to-read,
+3,,Nobody,,,0,0,0,0,read,
+`
+
+type FakeBookRepoForImport struct {
+	FakeBookRepo
+}
+
+func (f *FakeBookRepoForImport) CreateBook(ctx context.Context, b *models.Book) error {
+	b.ID = uint(len(f.Books) + 1)
+	return f.FakeBookRepo.CreateBook(ctx, b)
+}
+
+func (f *FakeBookRepoForImport) FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error) {
+	for _, b := range f.Books {
+		if strings.EqualFold(b.Title, title) && strings.EqualFold(b.Author, author) {
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestImportGoodreads_CreatesBooks(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForImport{}
+	progressRepo := &FakeProgressRepo{}
+	reviewRepo := &FakeReviewRepo{}
+	service := NewImportService(bookRepo, progressRepo, reviewRepo, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	report, err := service.ImportGoodreads(ctx, 1, strings.NewReader(goodreadsCSV), "")
+	if err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if report.Created != 2 || report.Failed != 1 {
+		t.Errorf("Expected 2 created and 1 failed, got %d created and %d failed", report.Created, report.Failed)
+	}
+
+	hobbit := bookRepo.Books[0]
+	if hobbit.ISBN != "9780618260300" {
+		t.Errorf("Expected ISBN13 to be cleaned, got %q", hobbit.ISBN)
+	}
+	if hobbit.PublicationYear != 1937 {
+		t.Errorf("Expected original publication year 1937, got %d", hobbit.PublicationYear)
+	}
+	if len(reviewRepo.Reviews) != 1 || reviewRepo.Reviews[0].Comment != "Loved it\nagain" {
+		t.Errorf("Expected one imported review, got %+v", reviewRepo.Reviews)
+	}
+}
+
+func TestImportGoodreads_SkipsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForImport{}
+	bookRepo.Books = []models.Book{{ID: 7, Title: "Dune", Author: "Frank Herbert"}}
+	service := NewImportService(bookRepo, &FakeProgressRepo{}, &FakeReviewRepo{}, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	report, err := service.ImportGoodreads(ctx, 1, strings.NewReader(goodreadsCSV), ImportModeSkip)
+	if err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if report.Skipped != 1 {
+		t.Errorf("Expected 1 skipped row, got %d", report.Skipped)
+	}
+	if report.Rows[1].BookID != 7 {
+		t.Errorf("Expected skipped row to point at existing book 7, got %d", report.Rows[1].BookID)
+	}
+}
+
+func TestImportGoodreads_MergeAdvancesStatus(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForImport{}
+	bookRepo.Books = []models.Book{{ID: 3, Title: "The Hobbit", Author: "J.R.R. Tolkien", TotalPages: 300}}
+	progressRepo := &FakeProgressRepo{
+		SavedData: &models.ReadingProgress{ID: 9, BookID: 3, Status: "Currently Reading", CurrentPage: 120},
+	}
+	service := NewImportService(bookRepo, progressRepo, &FakeReviewRepo{}, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	hobbitOnly := strings.Join(strings.Split(goodreadsCSV, "\n")[:2], "\n")
+	report, err := service.ImportGoodreads(ctx, 1, strings.NewReader(hobbitOnly), ImportModeMerge)
+	if err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if report.Merged != 1 {
+		t.Errorf("Expected 1 merged row, got %d", report.Merged)
+	}
+	if progressRepo.SavedData.Status != "Finished" || progressRepo.SavedData.ID != 9 {
+		t.Errorf("Expected existing progress to be marked Finished, got %+v", progressRepo.SavedData)
+	}
+}
+
+func TestImportGoodreads_ReviewsUseDefaultVisibility(t *testing.T) {
+	ctx := context.Background()
+	userRepo := &FakeUserRepo{Users: []models.User{{ID: 1, DefaultReviewVisibility: models.VisibilityPrivate}}}
+	hobbitOnly := strings.Join(strings.Split(goodreadsCSV, "\n")[:2], "\n")
+
+	// a new book
+	reviewRepo := &FakeReviewRepo{}
+	service := NewImportService(&FakeBookRepoForImport{}, &FakeProgressRepo{}, reviewRepo, &FakeWorkRepo{}, userRepo)
+	if _, err := service.ImportGoodreads(ctx, 1, strings.NewReader(hobbitOnly), ""); err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if len(reviewRepo.Reviews) != 1 || reviewRepo.Reviews[0].Visibility != models.VisibilityPrivate {
+		t.Errorf("Expected a private review for a new book, got %+v", reviewRepo.Reviews)
+	}
+
+	// a book merged into the library
+	bookRepo := &FakeBookRepoForImport{}
+	bookRepo.Books = []models.Book{{ID: 3, UserID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien"}}
+	reviewRepo = &FakeReviewRepo{}
+	service = NewImportService(bookRepo, &FakeProgressRepo{}, reviewRepo, &FakeWorkRepo{}, userRepo)
+	if _, err := service.ImportGoodreads(ctx, 1, strings.NewReader(hobbitOnly), ImportModeMerge); err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if len(reviewRepo.Reviews) != 1 || reviewRepo.Reviews[0].Visibility != models.VisibilityPrivate {
+		t.Errorf("Expected a private review for a merged book, got %+v", reviewRepo.Reviews)
+	}
+}
+
+func TestImportGoodreads_ReadWithoutDateReadUsesDateAdded(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForImport{}
+	progressRepo := &FakeProgressRepo{}
+	service := NewImportService(bookRepo, progressRepo, &FakeReviewRepo{}, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	csv := `Title,Author,Exclusive Shelf,Date Read,Date Added
+Emma,Jane Austen,read,,2019/03/04
+`
+	report, err := service.ImportGoodreads(ctx, 1, strings.NewReader(csv), "")
+	if err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+
+	want := time.Date(2019, 3, 4, 0, 0, 0, 0, time.UTC)
+	if progressRepo.SavedData.FinishedAt == nil || !progressRepo.SavedData.FinishedAt.Equal(want) {
+		t.Errorf("Expected finish date %v from Date Added, got %v", want, progressRepo.SavedData.FinishedAt)
+	}
+	if !strings.Contains(report.Rows[0].Message, "Date Added") {
+		t.Errorf("Expected the row to say the date was guessed, got %q", report.Rows[0].Message)
+	}
+}
+
+func TestImportGoodreads_ReadWithoutAnyDateIsReported(t *testing.T) {
+	ctx := context.Background()
+	progressRepo := &FakeProgressRepo{}
+	service := NewImportService(&FakeBookRepoForImport{}, progressRepo, &FakeReviewRepo{}, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	csv := `Title,Author,Exclusive Shelf
+Emma,Jane Austen,read
+`
+	report, err := service.ImportGoodreads(ctx, 1, strings.NewReader(csv), "")
+	if err != nil {
+		t.Fatalf("Expected import to succeed, but got error: %v", err)
+	}
+	if report.Created != 1 || progressRepo.SavedData.FinishedAt != nil {
+		t.Errorf("Expected the book to be imported without a finish date, got %+v", progressRepo.SavedData)
+	}
+	if report.Rows[0].Message == "" {
+		t.Error("Expected a warning about the missing finish date")
+	}
+}
+
+func TestImportGoodreads_NotGoodreadsFile(t *testing.T) {
+	ctx := context.Background()
+	service := NewImportService(&FakeBookRepoForImport{}, &FakeProgressRepo{}, &FakeReviewRepo{}, &FakeWorkRepo{}, &FakeUserRepo{})
+
+	_, err := service.ImportGoodreads(ctx, 1, strings.NewReader("name,price\nfoo,1\n"), "")
+	if err == nil {
+		t.Errorf("Expected error for a non-Goodreads CSV, but got nil")
+	}
+}
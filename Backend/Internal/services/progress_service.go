@@ -1,18 +1,46 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidStatus        = errors.New("invalid reading status")
+	ErrInvalidTransition    = errors.New("invalid status change")
+	ErrProgressAccessDenied = errors.New("access denied: you do not own this book")
+	ErrInvalidPage          = errors.New("invalid page")
+	ErrInvalidSession       = errors.New("invalid reading session")
+	ErrNoProgress           = errors.New("this book has no reading progress yet")
+	ErrInvalidDates         = errors.New("invalid reading dates")
+	ErrRereadNotFinished    = errors.New("you can only re-read a book you have finished")
+)
+
+// statusTransitions lists where each status may move next (staying put is
+// always allowed). A finished read is closed; re-reading starts a new one.
+var statusTransitions = map[models.ReadingStatus][]models.ReadingStatus{
+	models.StatusWantToRead:   {models.StatusReading, models.StatusFinished},
+	models.StatusReading:      {models.StatusPaused, models.StatusFinished, models.StatusDidNotFinish, models.StatusWantToRead},
+	models.StatusPaused:       {models.StatusReading, models.StatusFinished, models.StatusDidNotFinish, models.StatusWantToRead},
+	models.StatusFinished:     {},
+	models.StatusDidNotFinish: {models.StatusReading, models.StatusWantToRead},
+}
+
 type ProgressService interface {
-	UpdateProgress(userID uint, bookID uint, req dto.UpdateProgressRequest) error
-	GetProgress(userID uint, bookID uint) (*models.ReadingProgress, error)
+	UpdateProgress(ctx context.Context, userID uint, bookID uint, req dto.UpdateProgressRequest) error
+	GetProgress(ctx context.Context, userID uint, bookID uint) (*models.ReadingProgress, error)
+	LogSession(ctx context.Context, userID uint, bookID uint, req dto.CreateSessionRequest) (*models.ReadingSession, error)
+	GetSessions(ctx context.Context, userID uint, bookID uint) ([]models.ReadingSession, error)
+	UpdateDates(ctx context.Context, userID uint, bookID uint, req dto.UpdateProgressDatesRequest) (*models.ReadingProgress, error)
+	StartReread(ctx context.Context, userID uint, bookID uint) (*models.ReadingProgress, error)
 }
 
 type progressService struct {
@@ -27,53 +55,79 @@ func NewProgressService(repo repository.ProgressRepository, bookRepo repository.
 	}
 }
 
-func (s *progressService) UpdateProgress(userID uint, bookID uint, req dto.UpdateProgressRequest) error {
-	book, err := s.bookRepo.GetBookByID(bookID, userID)
+func (s *progressService) UpdateProgress(ctx context.Context, userID uint, bookID uint, req dto.UpdateProgressRequest) error {
+	book, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
 	if err != nil {
-		return errors.New("access denied: you do not own this book")
+		return ErrProgressAccessDenied
+	}
+
+	if !req.Status.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, req.Status)
 	}
 
-	if req.Status == "Want to Read" {
+	if req.Status == models.StatusWantToRead {
 		req.CurrentPage = 0
 	}
 
 	if req.CurrentPage > book.TotalPages {
-		return fmt.Errorf("invalid page: this book only has %d pages", book.TotalPages)
+		return fmt.Errorf("%w: this book only has %d pages", ErrInvalidPage, book.TotalPages)
 	}
 
 	if req.CurrentPage == book.TotalPages && book.TotalPages > 0 {
-		req.Status = "Finished"
+		req.Status = models.StatusFinished
 	}
 
-	if req.Status == "Finished" && req.CurrentPage < book.TotalPages {
-		return fmt.Errorf("to mark as Finished, you must reach the final page (%d)", book.TotalPages)
+	if req.Status == models.StatusFinished && req.CurrentPage < book.TotalPages {
+		return fmt.Errorf("%w: to mark as Finished, you must reach the final page (%d)", ErrInvalidPage, book.TotalPages)
 	}
 
-	progress, err := s.repo.GetByBookID(bookID)
+	progress, err := s.repo.GetByBookID(ctx, bookID)
 	if err != nil {
-		progress = &models.ReadingProgress{BookID: bookID}
+		progress = &models.ReadingProgress{BookID: bookID, ReadNumber: 1, IsCurrent: true, Status: models.StatusWantToRead}
+	}
+
+	if !canTransition(progress.Status, req.Status) {
+		return fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidTransition, progress.Status, req.Status)
+	}
+
+	now := time.Now()
+	startedAt := now
+	if req.StartedAt != nil && req.StartedAt.Before(now) {
+		startedAt = *req.StartedAt
 	}
 
+	startPage := progress.CurrentPage
+	setStatusDates(progress, req.Status, startedAt, now)
 	progress.CurrentPage = req.CurrentPage
 	progress.Status = req.Status
 
-	return s.repo.Save(progress)
+	// only a bookmark that moves forward is reading; status changes and
+	// resets would show up as empty or negative sessions in stats
+	var session *models.ReadingSession
+	if req.CurrentPage > startPage {
+		session = newSession(bookID, startPage, req.CurrentPage, startedAt, now, req.Note)
+	}
+	if err := s.repo.SaveWithSession(ctx, progress, session); err != nil {
+		return err
+	}
+	metrics.ProgressUpdates.WithLabelValues(string(progress.Status)).Inc()
+	return nil
 }
 
-func (s *progressService) GetProgress(userID uint, bookID uint) (*models.ReadingProgress, error) {
+func (s *progressService) GetProgress(ctx context.Context, userID uint, bookID uint) (*models.ReadingProgress, error) {
 
-	_, err := s.bookRepo.GetBookByID(bookID, userID)
+	_, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
 	if err != nil {
 		return nil, errors.New("access denied")
 	}
 
-	progress, err := s.repo.GetByBookID(bookID)
+	progress, err := s.repo.GetByBookID(ctx, bookID)
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || err.Error() == "record not found" {
 			return &models.ReadingProgress{
 				BookID:      bookID,
-				Status:      "Want to Read",
+				Status:      models.StatusWantToRead,
 				CurrentPage: 0,
 			}, nil
 		}
@@ -82,3 +136,171 @@ func (s *progressService) GetProgress(userID uint, bookID uint) (*models.Reading
 
 	return progress, nil
 }
+
+func (s *progressService) LogSession(ctx context.Context, userID uint, bookID uint, req dto.CreateSessionRequest) (*models.ReadingSession, error) {
+	book, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, ErrProgressAccessDenied
+	}
+
+	if req.EndPage < req.StartPage {
+		return nil, fmt.Errorf("%w: end page cannot be before start page", ErrInvalidSession)
+	}
+	if req.EndPage > book.TotalPages {
+		return nil, fmt.Errorf("%w: this book only has %d pages", ErrInvalidPage, book.TotalPages)
+	}
+	if req.EndedAt.Before(req.StartedAt) {
+		return nil, fmt.Errorf("%w: session cannot end before it started", ErrInvalidSession)
+	}
+	if req.EndedAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: session cannot end in the future", ErrInvalidSession)
+	}
+
+	// a logged session moves the bookmark forward, never back
+	progress, err := s.repo.GetByBookID(ctx, bookID)
+	if err != nil {
+		progress = &models.ReadingProgress{BookID: bookID, ReadNumber: 1, IsCurrent: true, Status: models.StatusWantToRead}
+	}
+	advance := req.EndPage > progress.CurrentPage
+	status := models.StatusReading
+	if book.TotalPages > 0 && req.EndPage == book.TotalPages {
+		status = models.StatusFinished
+	}
+	if advance && !canTransition(progress.Status, status) {
+		return nil, fmt.Errorf("%w: cannot go from %s to %s", ErrInvalidTransition, progress.Status, status)
+	}
+
+	session := newSession(bookID, req.StartPage, req.EndPage, req.StartedAt, req.EndedAt, req.Note)
+	var moved *models.ReadingProgress
+	if advance {
+		setStatusDates(progress, status, req.StartedAt, req.EndedAt)
+		progress.CurrentPage = req.EndPage
+		progress.Status = status
+		moved = progress
+	}
+	if err := s.repo.SaveWithSession(ctx, moved, session); err != nil {
+		return nil, err
+	}
+
+	metrics.ProgressUpdates.WithLabelValues(string(progress.Status)).Inc()
+	return session, nil
+}
+
+// UpdateDates lets the user backdate when they started or finished a book,
+// e.g. for reads that happened before they joined.
+func (s *progressService) UpdateDates(ctx context.Context, userID uint, bookID uint, req dto.UpdateProgressDatesRequest) (*models.ReadingProgress, error) {
+	_, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, ErrProgressAccessDenied
+	}
+
+	progress, err := s.repo.GetByBookID(ctx, bookID)
+	if err != nil {
+		return nil, ErrNoProgress
+	}
+
+	now := time.Now()
+	if req.StartedAt != nil {
+		if req.StartedAt.After(now) {
+			return nil, fmt.Errorf("%w: start date cannot be in the future", ErrInvalidDates)
+		}
+		progress.StartedAt = req.StartedAt
+	}
+	if req.FinishedAt != nil {
+		if progress.Status != models.StatusFinished {
+			return nil, fmt.Errorf("%w: only finished books can have a finish date", ErrInvalidDates)
+		}
+		if req.FinishedAt.After(now) {
+			return nil, fmt.Errorf("%w: finish date cannot be in the future", ErrInvalidDates)
+		}
+		progress.FinishedAt = req.FinishedAt
+	}
+	if progress.StartedAt != nil && progress.FinishedAt != nil && progress.FinishedAt.Before(*progress.StartedAt) {
+		return nil, fmt.Errorf("%w: finish date cannot be before start date", ErrInvalidDates)
+	}
+
+	if err := s.repo.Save(ctx, progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// StartReread keeps the finished read-through in the history and opens a
+// fresh one, so a re-read gets its own progress, dates and review.
+func (s *progressService) StartReread(ctx context.Context, userID uint, bookID uint) (*models.ReadingProgress, error) {
+	_, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, ErrProgressAccessDenied
+	}
+
+	current, err := s.repo.GetByBookID(ctx, bookID)
+	if err != nil || current.Status != models.StatusFinished {
+		return nil, ErrRereadNotFinished
+	}
+
+	now := time.Now()
+	next := &models.ReadingProgress{
+		BookID:     bookID,
+		ReadNumber: current.ReadNumber + 1,
+		IsCurrent:  true,
+		Status:     models.StatusReading,
+		StartedAt:  &now,
+	}
+	if err := s.repo.StartNewRead(ctx, current, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+func (s *progressService) GetSessions(ctx context.Context, userID uint, bookID uint) ([]models.ReadingSession, error) {
+	_, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, errors.New("access denied")
+	}
+
+	return s.repo.GetSessionsByBookID(ctx, bookID)
+}
+
+func newSession(bookID uint, startPage, endPage int, startedAt, endedAt time.Time, note string) *models.ReadingSession {
+	return &models.ReadingSession{
+		BookID:          bookID,
+		StartPage:       startPage,
+		EndPage:         endPage,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationMinutes: int(endedAt.Sub(startedAt).Minutes()),
+		Note:            note,
+	}
+}
+
+// setStatusDates stamps StartedAt/FinishedAt on status transitions. Edits that
+// keep the same status leave the dates alone, so fixing a typo in a finished
+// book does not move its finish date.
+func setStatusDates(progress *models.ReadingProgress, newStatus models.ReadingStatus, startedAt, at time.Time) {
+	if newStatus != models.StatusWantToRead && progress.StartedAt == nil {
+		progress.StartedAt = &startedAt
+	}
+
+	switch {
+	case newStatus == models.StatusFinished && progress.Status != models.StatusFinished:
+		progress.FinishedAt = &at
+	case newStatus != models.StatusFinished:
+		progress.FinishedAt = nil
+	}
+
+	if newStatus == models.StatusWantToRead {
+		progress.StartedAt = nil
+	}
+}
+
+func canTransition(from, to models.ReadingStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
@@ -10,10 +12,12 @@ import (
 
 type FakeProgressRepo struct {
 	SavedData *models.ReadingProgress
+	Retired   *models.ReadingProgress
+	Sessions  []models.ReadingSession
 	MockErr   error
 }
 
-func (f *FakeProgressRepo) GetByBookID(id uint) (*models.ReadingProgress, error) {
+func (f *FakeProgressRepo) GetByBookID(ctx context.Context, id uint) (*models.ReadingProgress, error) {
 	if f.MockErr != nil {
 		return nil, f.MockErr
 	}
@@ -23,64 +27,104 @@ func (f *FakeProgressRepo) GetByBookID(id uint) (*models.ReadingProgress, error)
 	return f.SavedData, nil
 }
 
-func (f *FakeProgressRepo) Save(p *models.ReadingProgress) error {
+func (f *FakeProgressRepo) Save(ctx context.Context, p *models.ReadingProgress) error {
 	f.SavedData = p
 	return f.MockErr
 }
 
+func (f *FakeProgressRepo) StartNewRead(ctx context.Context, current *models.ReadingProgress, next *models.ReadingProgress) error {
+	current.IsCurrent = false
+	f.Retired = current
+	f.SavedData = next
+	return f.MockErr
+}
+
+func (f *FakeProgressRepo) SaveWithSession(ctx context.Context, p *models.ReadingProgress, s *models.ReadingSession) error {
+	if f.MockErr != nil {
+		return f.MockErr
+	}
+	if p != nil {
+		f.SavedData = p
+	}
+	if s != nil {
+		f.Sessions = append(f.Sessions, *s)
+	}
+	return nil
+}
+
+func (f *FakeProgressRepo) GetSessionsByBookID(ctx context.Context, bookID uint) ([]models.ReadingSession, error) {
+	return f.Sessions, f.MockErr
+}
+
 type FakeBookRepoForProgress struct {
 	UserOwnsBook bool
 }
 
-func (f *FakeBookRepoForProgress) GetBookByID(id uint, uid uint) (*models.Book, error) {
+func (f *FakeBookRepoForProgress) GetBookByID(ctx context.Context, id uint, uid uint) (*models.Book, error) {
 	if !f.UserOwnsBook {
 		return nil, errors.New("access denied")
 	}
 	return &models.Book{ID: id, UserID: uid, TotalPages: 300}, nil
 }
 
-func (f *FakeBookRepoForProgress) CreateBook(b *models.Book) error                  { return nil }
-func (f *FakeBookRepoForProgress) GetBooksByUserID(uid uint) ([]models.Book, error) { return nil, nil }
-func (f *FakeBookRepoForProgress) UpdateBook(bid, uid uint, b *models.Book) error   { return nil }
-func (f *FakeBookRepoForProgress) DeleteBook(id, uid uint) error                    { return nil }
-func (f *FakeBookRepoForProgress) SearchBooks(userID uint, query string) ([]models.Book, error) {
+func (f *FakeBookRepoForProgress) CreateBook(ctx context.Context, b *models.Book) error { return nil }
+func (f *FakeBookRepoForProgress) GetBooksByUserID(ctx context.Context, uid uint) ([]models.Book, error) {
+	return nil, nil
+}
+func (f *FakeBookRepoForProgress) GetBookWithHistory(ctx context.Context, id uint, uid uint) (*models.Book, error) {
+	return f.GetBookByID(ctx, id, uid)
+}
+func (f *FakeBookRepoForProgress) GetReadHistories(ctx context.Context, uid uint, bookIDs []uint) ([]models.ReadingProgress, error) {
+	return nil, nil
+}
+func (f *FakeBookRepoForProgress) ListBooks(ctx context.Context, uid uint, q dto.BookListQuery) ([]models.Book, string, error) {
+	return nil, "", nil
+}
+func (f *FakeBookRepoForProgress) UpdateBook(ctx context.Context, bid, uid uint, b *models.Book) error {
+	return nil
+}
+func (f *FakeBookRepoForProgress) DeleteBook(ctx context.Context, id, uid uint) error { return nil }
+func (f *FakeBookRepoForProgress) SearchBooks(ctx context.Context, userID uint, query string) ([]models.Book, error) {
 	return []models.Book{}, nil
 }
-func (f *FakeBookRepoForProgress) GetDashboardStats(userID uint) (dto.DashboardStats, error) {
+func (f *FakeBookRepoForProgress) GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error) {
 	return dto.DashboardStats{}, nil
 }
 
-func (f *FakeBookRepoForProgress) FindDuplicate(userID uint, title string, author string, isbn string) (*models.Book, error) {
+func (f *FakeBookRepoForProgress) FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error) {
 	return nil, nil
 }
 
 func TestUpdateProgress_PageOverflow(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
 	progressRepo := &FakeProgressRepo{}
 	service := NewProgressService(progressRepo, bookRepo)
-	req := dto.UpdateProgressRequest{CurrentPage: 350, Status: "Reading"}
-	err := service.UpdateProgress(1, 10, req)
+	req := dto.UpdateProgressRequest{CurrentPage: 350, Status: "Currently Reading"}
+	err := service.UpdateProgress(ctx, 1, 10, req)
 	if err == nil {
 		t.Errorf("Expected error for page overflow, but got nil")
 	}
 }
 
 func TestUpdateProgress_FinishedInvalidPage(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
 	progressRepo := &FakeProgressRepo{}
 	service := NewProgressService(progressRepo, bookRepo)
 	req := dto.UpdateProgressRequest{CurrentPage: 50, Status: "Finished"}
-	err := service.UpdateProgress(1, 10, req)
+	err := service.UpdateProgress(ctx, 1, 10, req)
 	if err == nil {
 		t.Errorf("Expected error when marking Finished early, but got nil")
 	}
 }
 
 func TestGetProgress_NotFoundDefault(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
 	progressRepo := &FakeProgressRepo{SavedData: nil}
 	service := NewProgressService(progressRepo, bookRepo)
-	result, err := service.GetProgress(1, 10)
+	result, err := service.GetProgress(ctx, 1, 10)
 	if err != nil {
 		t.Errorf("Expected success, but got error: %v", err)
 	}
@@ -90,11 +134,12 @@ func TestGetProgress_NotFoundDefault(t *testing.T) {
 }
 
 func TestUpdateProgress_NewEntry(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
 	progressRepo := &FakeProgressRepo{SavedData: nil}
 	service := NewProgressService(progressRepo, bookRepo)
-	req := dto.UpdateProgressRequest{CurrentPage: 50, Status: "Reading"}
-	err := service.UpdateProgress(1, 10, req)
+	req := dto.UpdateProgressRequest{CurrentPage: 50, Status: "Currently Reading"}
+	err := service.UpdateProgress(ctx, 1, 10, req)
 	if err != nil {
 		t.Fatalf("Error: %v", err)
 	}
@@ -104,11 +149,12 @@ func TestUpdateProgress_NewEntry(t *testing.T) {
 }
 
 func TestGetProgress_Success(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
 	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 100, Status: "Finished"}
 	progressRepo := &FakeProgressRepo{SavedData: existing}
 	service := NewProgressService(progressRepo, bookRepo)
-	result, err := service.GetProgress(1, 10)
+	result, err := service.GetProgress(ctx, 1, 10)
 	if err != nil {
 		t.Fatalf("Error: %v", err)
 	}
@@ -118,12 +164,270 @@ func TestGetProgress_Success(t *testing.T) {
 }
 
 func TestUpdateProgress_SecurityFailure(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: false}
+	progressRepo := &FakeProgressRepo{}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 10, Status: "Currently Reading"}
+	err := service.UpdateProgress(ctx, 1, 99, req)
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestUpdateProgress_RecordsSession(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 40, Status: "Currently Reading"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 90, Status: "Currently Reading", Note: "train ride"}
+	if err := service.UpdateProgress(ctx, 1, 10, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(progressRepo.Sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(progressRepo.Sessions))
+	}
+	session := progressRepo.Sessions[0]
+	if session.StartPage != 40 || session.EndPage != 90 {
+		t.Errorf("Expected pages 40-90, got %d-%d", session.StartPage, session.EndPage)
+	}
+	if session.Note != "train ride" {
+		t.Errorf("Got note %q", session.Note)
+	}
+}
+
+func TestUpdateProgress_NoSessionWithoutNewPages(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	progressRepo := &FakeProgressRepo{SavedData: &models.ReadingProgress{BookID: 10, CurrentPage: 50, Status: models.StatusReading}}
+	service := NewProgressService(progressRepo, bookRepo)
+
+	// a status-only change
+	if err := service.UpdateProgress(ctx, 1, 10, dto.UpdateProgressRequest{CurrentPage: 50, Status: models.StatusPaused}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	// a reset to Want to Read
+	if err := service.UpdateProgress(ctx, 1, 10, dto.UpdateProgressRequest{CurrentPage: 50, Status: models.StatusWantToRead}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if progressRepo.SavedData.CurrentPage != 0 || progressRepo.SavedData.Status != models.StatusWantToRead {
+		t.Errorf("Expected the book to be reset, got %+v", progressRepo.SavedData)
+	}
+	if len(progressRepo.Sessions) != 0 {
+		t.Errorf("Expected no sessions, got %+v", progressRepo.Sessions)
+	}
+}
+
+func TestLogSession_Success(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	progressRepo := &FakeProgressRepo{}
+	service := NewProgressService(progressRepo, bookRepo)
+	end := time.Now().Add(-time.Hour)
+	req := dto.CreateSessionRequest{StartPage: 0, EndPage: 60, StartedAt: end.Add(-45 * time.Minute), EndedAt: end}
+	session, err := service.LogSession(ctx, 1, 10, req)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if session.DurationMinutes != 45 {
+		t.Errorf("Expected 45 minutes, got %d", session.DurationMinutes)
+	}
+	if progressRepo.SavedData == nil || progressRepo.SavedData.CurrentPage != 60 {
+		t.Errorf("Expected bookmark to move to page 60")
+	}
+}
+
+func TestLogSession_EndBeforeStart(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	progressRepo := &FakeProgressRepo{}
+	service := NewProgressService(progressRepo, bookRepo)
+	now := time.Now()
+	req := dto.CreateSessionRequest{StartPage: 50, EndPage: 20, StartedAt: now.Add(-time.Hour), EndedAt: now}
+	_, err := service.LogSession(ctx, 1, 10, req)
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for reversed page range, got %v", err)
+	}
+}
+
+func TestLogSession_InFuture(t *testing.T) {
+	ctx := context.Background()
+	service := NewProgressService(&FakeProgressRepo{}, &FakeBookRepoForProgress{UserOwnsBook: true})
+	now := time.Now()
+	req := dto.CreateSessionRequest{StartPage: 0, EndPage: 20, StartedAt: now, EndedAt: now.Add(time.Hour)}
+	_, err := service.LogSession(ctx, 1, 10, req)
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for a session ending in the future, got %v", err)
+	}
+}
+
+func TestLogSession_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	service := NewProgressService(&FakeProgressRepo{}, &FakeBookRepoForProgress{UserOwnsBook: false})
+	now := time.Now()
+	req := dto.CreateSessionRequest{StartPage: 0, EndPage: 20, StartedAt: now.Add(-time.Hour), EndedAt: now}
+	_, err := service.LogSession(ctx, 1, 10, req)
+	if !errors.Is(err, ErrProgressAccessDenied) {
+		t.Errorf("Expected ErrProgressAccessDenied, got %v", err)
+	}
+}
+
+func TestGetSessions_SecurityFailure(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: false}
 	progressRepo := &FakeProgressRepo{}
 	service := NewProgressService(progressRepo, bookRepo)
-	req := dto.UpdateProgressRequest{CurrentPage: 10, Status: "Reading"}
-	err := service.UpdateProgress(1, 99, req)
+	_, err := service.GetSessions(ctx, 1, 99)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
 }
+
+func TestUpdateProgress_FinishSetsFinishedAt(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 200, Status: "Currently Reading"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 300, Status: "Finished"}
+	if err := service.UpdateProgress(ctx, 1, 10, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if progressRepo.SavedData.FinishedAt == nil || progressRepo.SavedData.StartedAt == nil {
+		t.Errorf("Expected start and finish dates to be set, got %+v", progressRepo.SavedData)
+	}
+}
+
+func TestUpdateProgress_ResavingFinishedKeepsDate(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	finishedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 300, Status: "Finished", FinishedAt: &finishedAt}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 300, Status: "Finished"}
+	if err := service.UpdateProgress(ctx, 1, 10, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !progressRepo.SavedData.FinishedAt.Equal(finishedAt) {
+		t.Errorf("Expected finish date to stay %v, got %v", finishedAt, progressRepo.SavedData.FinishedAt)
+	}
+}
+
+func TestUpdateDates_Backdate(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 300, Status: "Finished"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	started := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	finished := time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC)
+	progress, err := service.UpdateDates(ctx, 1, 10, dto.UpdateProgressDatesRequest{StartedAt: &started, FinishedAt: &finished})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !progress.FinishedAt.Equal(finished) {
+		t.Errorf("Expected finish date %v, got %v", finished, progress.FinishedAt)
+	}
+}
+
+func TestUpdateDates_FinishBeforeStart(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 300, Status: "Finished"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	started := time.Date(2023, 2, 3, 0, 0, 0, 0, time.UTC)
+	finished := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	_, err := service.UpdateDates(ctx, 1, 10, dto.UpdateProgressDatesRequest{StartedAt: &started, FinishedAt: &finished})
+	if !errors.Is(err, ErrInvalidDates) {
+		t.Errorf("Expected ErrInvalidDates for finish before start, got %v", err)
+	}
+}
+
+func TestStartReread_Success(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{ID: 4, BookID: 10, ReadNumber: 1, IsCurrent: true, CurrentPage: 300, Status: "Finished"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	next, err := service.StartReread(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if next.ReadNumber != 2 || next.Status != "Currently Reading" || next.CurrentPage != 0 {
+		t.Errorf("Expected a fresh second read-through, got %+v", next)
+	}
+	if progressRepo.Retired == nil || progressRepo.Retired.IsCurrent {
+		t.Errorf("Expected the finished read-through to be kept as history")
+	}
+}
+
+func TestStartReread_NotFinished(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, ReadNumber: 1, IsCurrent: true, CurrentPage: 120, Status: "Currently Reading"}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	_, err := service.StartReread(ctx, 1, 10)
+	if !errors.Is(err, ErrRereadNotFinished) {
+		t.Errorf("Expected ErrRereadNotFinished when re-reading an unfinished book, got %v", err)
+	}
+}
+
+func TestUpdateProgress_InvalidStatus(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	progressRepo := &FakeProgressRepo{SavedData: nil}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 50, Status: "Skimming"}
+	err := service.UpdateProgress(ctx, 1, 10, req)
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("Expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestUpdateProgress_InvalidTransition(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 300, Status: models.StatusFinished}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 120, Status: models.StatusPaused}
+	err := service.UpdateProgress(ctx, 1, 10, req)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+	if existing.Status != models.StatusFinished {
+		t.Errorf("Expected status to stay Finished, got %s", existing.Status)
+	}
+}
+
+func TestUpdateProgress_FinishedCannotBeReopened(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 300, Status: models.StatusFinished}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 120, Status: models.StatusReading}
+	err := service.UpdateProgress(ctx, 1, 10, req)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, a re-read should be started instead, got %v", err)
+	}
+}
+
+func TestUpdateProgress_DidNotFinish(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForProgress{UserOwnsBook: true}
+	existing := &models.ReadingProgress{BookID: 10, CurrentPage: 80, Status: models.StatusPaused}
+	progressRepo := &FakeProgressRepo{SavedData: existing}
+	service := NewProgressService(progressRepo, bookRepo)
+	req := dto.UpdateProgressRequest{CurrentPage: 80, Status: models.StatusDidNotFinish}
+	if err := service.UpdateProgress(ctx, 1, 10, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if progressRepo.SavedData.Status != models.StatusDidNotFinish || progressRepo.SavedData.FinishedAt != nil {
+		t.Errorf("Expected an unfinished DNF read, got %+v", progressRepo.SavedData)
+	}
+}
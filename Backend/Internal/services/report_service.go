@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+)
+
+const (
+	ReportFormatJSON     = "json"
+	ReportFormatHTML     = "html"
+	ReportFormatMarkdown = "markdown"
+
+	reportTopN = 5
+)
+
+var (
+	ErrInvalidReportYear       = errors.New("invalid year")
+	ErrUnsupportedReportFormat = errors.New("unsupported report format, use json, html or markdown")
+)
+
+var (
+	reportHTML     = htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(reportHTMLTemplate))
+	reportMarkdown = template.Must(template.New("report").Funcs(reportFuncs).Parse(reportMarkdownTemplate))
+)
+
+type ReportService interface {
+	GetYearReport(ctx context.Context, userID uint, year int) (*dto.YearReport, error)
+	Render(report *dto.YearReport, format string, w io.Writer) error
+}
+
+type reportService struct {
+	statsRepo repository.StatsRepository
+	goalRepo  repository.GoalRepository
+}
+
+func NewReportService(statsRepo repository.StatsRepository, goalRepo repository.GoalRepository) ReportService {
+	return &reportService{statsRepo: statsRepo, goalRepo: goalRepo}
+}
+
+// GetYearReport recaps the read-throughs finished during the calendar year.
+func (s *reportService) GetYearReport(ctx context.Context, userID uint, year int) (*dto.YearReport, error) {
+	if year < 1900 || year > time.Now().Year()+1 {
+		return nil, ErrInvalidReportYear
+	}
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	reads, err := s.statsRepo.GetFinishedReads(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.statsRepo.GetPagesPerDay(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.YearReport{
+		Year:          year,
+		BooksFinished: len(reads),
+		BooksPerMonth: booksPerMonth(reads, start, end.AddDate(0, 0, -1)),
+		TopGenres:     topN(breakdown(reads, func(r dto.FinishedRead) string { return r.Genre })),
+		TopAuthors:    topN(breakdown(reads, func(r dto.FinishedRead) string { return r.Author })),
+		HighestRated:  []dto.ReportBook{},
+	}
+	for _, d := range days {
+		report.PagesRead += d.Pages
+	}
+
+	var ratingSum, rated int
+	var rankedByRating []dto.FinishedRead
+	for _, r := range reads {
+		if r.TotalPages > 0 {
+			if report.LongestBook == nil || r.TotalPages > report.LongestBook.Pages {
+				report.LongestBook = reportBook(r)
+			}
+			if report.ShortestBook == nil || r.TotalPages < report.ShortestBook.Pages {
+				report.ShortestBook = reportBook(r)
+			}
+		}
+		if r.Rating > 0 {
+			ratingSum += r.Rating
+			rated++
+			rankedByRating = append(rankedByRating, r)
+		}
+	}
+	if rated > 0 {
+		avg := math.Round(float64(ratingSum)/float64(rated)*10) / 10
+		report.AverageRating = &avg
+	}
+
+	// reads come back in finish order, so equal ratings keep the earlier read first
+	sort.SliceStable(rankedByRating, func(i, j int) bool { return rankedByRating[i].Rating > rankedByRating[j].Rating })
+	for i := 0; i < len(rankedByRating) && i < reportTopN; i++ {
+		report.HighestRated = append(report.HighestRated, *reportBook(rankedByRating[i]))
+	}
+
+	report.MonthlyGoals, err = s.monthlyGoalHitRate(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *reportService) monthlyGoalHitRate(ctx context.Context, userID uint, year int) (dto.GoalHitRate, error) {
+	var rate dto.GoalHitRate
+	goals, err := s.goalRepo.GetGoalsByUserID(ctx, userID)
+	if err != nil {
+		return rate, err
+	}
+
+	now := time.Now()
+	for i := range goals {
+		goal := &goals[i]
+		if goal.Type != models.GoalTypeMonthly || goal.Year != year {
+			continue
+		}
+		start, end := goalPeriod(goal)
+
+		var current int64
+		target := goal.TargetBooks
+		if goal.Metric == models.GoalMetricPages {
+			target = goal.TargetPages
+			current, err = s.goalRepo.SumPagesReadBetween(ctx, userID, start, end)
+		} else {
+			current, err = s.goalRepo.CountFinishedBooksBetween(ctx, userID, start, end)
+		}
+		if err != nil {
+			return rate, err
+		}
+
+		hit := target > 0 && current >= int64(target)
+		if !hit && now.Before(end) {
+			continue
+		}
+		rate.MonthsWithGoal++
+		if hit {
+			rate.MonthsHit++
+		}
+	}
+	if rate.MonthsWithGoal > 0 {
+		rate.HitRate = math.Round(float64(rate.MonthsHit)/float64(rate.MonthsWithGoal)*1000) / 1000
+	}
+	return rate, nil
+}
+
+func (s *reportService) Render(report *dto.YearReport, format string, w io.Writer) error {
+	switch format {
+	case ReportFormatJSON:
+		return json.NewEncoder(w).Encode(report)
+	case ReportFormatHTML:
+		return reportHTML.Execute(w, report)
+	case ReportFormatMarkdown:
+		return reportMarkdown.Execute(w, report)
+	}
+	return ErrUnsupportedReportFormat
+}
+
+func reportBook(r dto.FinishedRead) *dto.ReportBook {
+	return &dto.ReportBook{
+		Title:      r.Title,
+		Author:     r.Author,
+		Pages:      r.TotalPages,
+		Rating:     r.Rating,
+		FinishedOn: r.FinishedAt.Format(dateLayout),
+	}
+}
+
+func topN(items []dto.StatsBreakdown) []dto.StatsBreakdown {
+	if len(items) > reportTopN {
+		return items[:reportTopN]
+	}
+	return items
+}
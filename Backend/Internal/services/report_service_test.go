@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+func TestGetYearReport(t *testing.T) {
+	ctx := context.Background()
+	statsRepo := &FakeStatsRepo{
+		Reads: []dto.FinishedRead{
+			{Title: "Dune", Author: "Herbert", Genre: "Sci-Fi", TotalPages: 600, Rating: 5, FinishedAt: day("2024-01-20")},
+			{Title: "Novella", Author: "Chiang", Genre: "Sci-Fi", TotalPages: 90, Rating: 3, FinishedAt: day("2024-02-02")},
+			{Title: "Earthsea", Author: "Le Guin", Genre: "Fantasy", TotalPages: 200, Rating: 5, FinishedAt: day("2024-03-15")},
+			{Title: "Unrated", Author: "Le Guin", TotalPages: 0, FinishedAt: day("2024-03-20")},
+		},
+	}
+	goalRepo := &FakeGoalRepo{
+		Goal:  &models.ReadingGoal{Type: models.GoalTypeMonthly, Metric: models.GoalMetricBooks, Year: 2024, Month: 1, TargetBooks: 1},
+		Count: 1,
+	}
+	service := NewReportService(statsRepo, goalRepo)
+
+	report, err := service.GetYearReport(ctx, 1, 2024)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if report.BooksFinished != 4 {
+		t.Errorf("Expected 4 books, got %d", report.BooksFinished)
+	}
+	if report.LongestBook.Title != "Dune" || report.ShortestBook.Title != "Novella" {
+		t.Errorf("Unexpected longest/shortest %+v / %+v", report.LongestBook, report.ShortestBook)
+	}
+	if len(report.HighestRated) != 3 || report.HighestRated[0].Title != "Dune" || report.HighestRated[1].Title != "Earthsea" {
+		t.Errorf("Unexpected highest rated %+v", report.HighestRated)
+	}
+	if report.AverageRating == nil || *report.AverageRating != 4.3 {
+		t.Errorf("Expected average rating 4.3, got %v", report.AverageRating)
+	}
+	if len(report.BooksPerMonth) != 12 || report.TopGenres[0].Name != "Sci-Fi" {
+		t.Errorf("Unexpected months/genres %d / %+v", len(report.BooksPerMonth), report.TopGenres)
+	}
+	if report.MonthlyGoals.MonthsWithGoal != 1 || report.MonthlyGoals.HitRate != 1 {
+		t.Errorf("Expected the January goal to be hit, got %+v", report.MonthlyGoals)
+	}
+}
+
+func TestGetYearReport_PagesReadMatchesStats(t *testing.T) {
+	ctx := context.Background()
+	statsRepo := &FakeStatsRepo{
+		// a book finished this year but mostly read the year before
+		Reads: []dto.FinishedRead{{Title: "Dune", TotalPages: 600, FinishedAt: day("2024-01-20")}},
+		Days: []dto.ReadingDay{
+			{Day: day("2024-01-19"), Pages: 40},
+			{Day: day("2024-01-20"), Pages: 60},
+		},
+	}
+	service := NewReportService(statsRepo, &FakeGoalRepo{})
+	stats := NewStatsService(statsRepo)
+
+	report, err := service.GetYearReport(ctx, 1, 2024)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if report.PagesRead != 100 {
+		t.Errorf("Expected the 100 pages logged in sessions, got %d", report.PagesRead)
+	}
+	yearStats, err := stats.GetStats(ctx, 1, dto.StatsQuery{From: "2024-01-01", To: "2024-12-31"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if yearStats.PagesRead != report.PagesRead {
+		t.Errorf("Expected the report and stats to agree, got %d and %d", report.PagesRead, yearStats.PagesRead)
+	}
+}
+
+func TestGetYearReport_InvalidYear(t *testing.T) {
+	ctx := context.Background()
+	service := NewReportService(&FakeStatsRepo{}, &FakeGoalRepo{})
+
+	if _, err := service.GetYearReport(ctx, 1, 1066); !errors.Is(err, ErrInvalidReportYear) {
+		t.Errorf("Expected ErrInvalidReportYear, got %v", err)
+	}
+}
+
+func TestRenderYearReport(t *testing.T) {
+	ctx := context.Background()
+	statsRepo := &FakeStatsRepo{
+		Reads: []dto.FinishedRead{
+			{Title: "Dune", Author: "Herbert", TotalPages: 600, Rating: 5, FinishedAt: day("2024-01-20")},
+			{Title: "Novella", Author: "Chiang", TotalPages: 90, Rating: 3, FinishedAt: day("2024-02-02")},
+			{Title: "Earthsea", Author: "Le Guin", TotalPages: 200, Rating: 5, FinishedAt: day("2024-03-15")},
+		},
+	}
+	service := NewReportService(statsRepo, &FakeGoalRepo{})
+	report, _ := service.GetYearReport(ctx, 1, 2024)
+
+	var md bytes.Buffer
+	if err := service.Render(report, ReportFormatMarkdown, &md); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !strings.Contains(md.String(), "# My 2024 in Books") || !strings.Contains(md.String(), "- **Average rating:** 4.3 / 5") {
+		t.Errorf("Unexpected markdown:\n%s", md.String())
+	}
+
+	var html bytes.Buffer
+	if err := service.Render(report, ReportFormatHTML, &html); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !strings.Contains(html.String(), "<em>Dune</em>") {
+		t.Errorf("Expected the longest book in the HTML report")
+	}
+
+	if err := service.Render(report, "pdf", &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedReportFormat) {
+		t.Errorf("Expected ErrUnsupportedReportFormat, got %v", err)
+	}
+}
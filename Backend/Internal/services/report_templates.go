@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var reportFuncs = map[string]interface{}{
+	"stars": func(n int) string { return strings.Repeat("★", n) },
+	"rating": func(avg *float64) string {
+		if avg == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.1f", *avg)
+	},
+	"percent": func(rate float64) string {
+		return fmt.Sprintf("%.0f%%", rate*100)
+	},
+	"monthName": func(month string) string {
+		t, err := time.Parse("2006-01", month)
+		if err != nil {
+			return month
+		}
+		return t.Format("January")
+	},
+}
+
+const reportMarkdownTemplate = `# My {{.Year}} in Books
+
+- **Books finished:** {{.BooksFinished}}
+- **Pages read:** {{.PagesRead}}
+{{- if .AverageRating}}
+- **Average rating:** {{rating .AverageRating}} / 5
+{{- end}}
+{{- with .LongestBook}}
+- **Longest book:** {{.Title}} by {{.Author}} ({{.Pages}} pages)
+{{- end}}
+{{- with .ShortestBook}}
+- **Shortest book:** {{.Title}} by {{.Author}} ({{.Pages}} pages)
+{{- end}}
+{{- if .MonthlyGoals.MonthsWithGoal}}
+- **Monthly goals hit:** {{.MonthlyGoals.MonthsHit}} of {{.MonthlyGoals.MonthsWithGoal}} ({{percent .MonthlyGoals.HitRate}})
+{{- end}}
+{{if .HighestRated}}
+## Highest rated
+
+{{range .HighestRated}}- {{stars .Rating}} {{.Title}} by {{.Author}}
+{{end}}{{end}}
+{{- if .TopGenres}}
+## Top genres
+
+{{range .TopGenres}}- {{.Name}}: {{.Books}}
+{{end}}{{end}}
+{{- if .TopAuthors}}
+## Top authors
+
+{{range .TopAuthors}}- {{.Name}}: {{.Books}}
+{{end}}{{end}}
+## Month by month
+
+| Month | Books |
+| --- | --- |
+{{range .BooksPerMonth}}| {{monthName .Month}} | {{.Books}} |
+{{end}}`
+
+const reportHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>My {{.Year}} in Books</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; color: #0f172a; }
+h1 { font-size: 2rem; }
+.stats { display: grid; grid-template-columns: repeat(2, 1fr); gap: 1rem; }
+.stat { background: #f1f5f9; border-radius: 1rem; padding: 1rem; }
+.stat strong { display: block; font-size: 1.5rem; }
+table { width: 100%; border-collapse: collapse; }
+td, th { text-align: left; padding: .25rem 0; border-bottom: 1px solid #e2e8f0; }
+</style>
+</head>
+<body>
+<h1>My {{.Year}} in Books</h1>
+<div class="stats">
+<div class="stat"><strong>{{.BooksFinished}}</strong>books finished</div>
+<div class="stat"><strong>{{.PagesRead}}</strong>pages read</div>
+{{- if .AverageRating}}
+<div class="stat"><strong>{{rating .AverageRating}}</strong>average rating</div>
+{{- end}}
+{{- if .MonthlyGoals.MonthsWithGoal}}
+<div class="stat"><strong>{{.MonthlyGoals.MonthsHit}} / {{.MonthlyGoals.MonthsWithGoal}}</strong>monthly goals hit ({{percent .MonthlyGoals.HitRate}})</div>
+{{- end}}
+</div>
+{{- with .LongestBook}}
+<p>Longest book: <em>{{.Title}}</em> by {{.Author}} ({{.Pages}} pages)</p>
+{{- end}}
+{{- with .ShortestBook}}
+<p>Shortest book: <em>{{.Title}}</em> by {{.Author}} ({{.Pages}} pages)</p>
+{{- end}}
+{{- if .HighestRated}}
+<h2>Highest rated</h2>
+<ul>
+{{- range .HighestRated}}
+<li>{{stars .Rating}} <em>{{.Title}}</em> by {{.Author}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .TopGenres}}
+<h2>Top genres</h2>
+<ul>
+{{- range .TopGenres}}
+<li>{{.Name}}: {{.Books}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .TopAuthors}}
+<h2>Top authors</h2>
+<ul>
+{{- range .TopAuthors}}
+<li>{{.Name}}: {{.Books}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<h2>Month by month</h2>
+<table>
+<tr><th>Month</th><th>Books</th></tr>
+{{- range .BooksPerMonth}}
+<tr><td>{{monthName .Month}}</td><td>{{.Books}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`
@@ -1,61 +1,281 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 )
 
+const (
+	defaultReviewPageSize = 10
+	defaultReviewSort     = "newest"
+)
+
+var (
+	ErrReviewNotFound     = errors.New("review not found")
+	ErrReviewAccessDenied = errors.New("access denied")
+	ErrOwnReviewVote      = errors.New("you cannot vote on your own review")
+)
+
 type ReviewService interface {
-	AddReview(userID uint, bookID uint, req dto.CreateReviewRequest) error
-	GetBookReviews(userID uint, bookID uint) ([]models.Review, error)
+	AddReview(ctx context.Context, userID uint, bookID uint, req dto.CreateReviewRequest) error
+	UpdateReview(ctx context.Context, userID uint, bookID uint, reviewID uint, req dto.UpdateReviewRequest) (*models.Review, error)
+	DeleteReview(ctx context.Context, userID uint, bookID uint, reviewID uint) error
+	GetReviewHistory(ctx context.Context, userID uint, bookID uint, reviewID uint) ([]models.ReviewRevision, error)
+	GetBookReviews(ctx context.Context, userID uint, bookID uint, query dto.ReviewFeedQuery) (*dto.ReviewFeed, error)
+	GetMyReviews(ctx context.Context, userID uint) ([]dto.MyReview, error)
+	MarkHelpful(ctx context.Context, userID uint, reviewID uint) error
+	UnmarkHelpful(ctx context.Context, userID uint, reviewID uint) error
 }
 
 type reviewService struct {
-	repo     repository.ReviewRepository
-	bookRepo repository.BookRepository
+	repo         repository.ReviewRepository
+	bookRepo     repository.BookRepository
+	progressRepo repository.ProgressRepository
+	userRepo     repository.UserRepository
 }
 
-func NewReviewService(repo repository.ReviewRepository, bookRepo repository.BookRepository) ReviewService {
+func NewReviewService(repo repository.ReviewRepository, bookRepo repository.BookRepository, progressRepo repository.ProgressRepository, userRepo repository.UserRepository) ReviewService {
 	return &reviewService{
-		repo:     repo,
-		bookRepo: bookRepo,
+		repo:         repo,
+		bookRepo:     bookRepo,
+		progressRepo: progressRepo,
+		userRepo:     userRepo,
 	}
 }
 
-func (s *reviewService) AddReview(userID uint, bookID uint, req dto.CreateReviewRequest) error {
+func (s *reviewService) AddReview(ctx context.Context, userID uint, bookID uint, req dto.CreateReviewRequest) error {
 
-	_, err := s.bookRepo.GetBookByID(bookID, userID)
+	_, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
 	if err != nil {
-		return errors.New("access denied")
+		return ErrReviewAccessDenied
 	}
 
-	existing, _ := s.repo.GetReviewByBookID(bookID)
-	if existing != nil {
-		return errors.New("you have already reviewed this book")
+	review := &models.Review{
+		BookID:     bookID,
+		UserID:     userID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		Visibility: req.Visibility,
+		IsSpoiler:  req.IsSpoiler,
+	}
+	if review.Visibility == "" {
+		review.Visibility = defaultReviewVisibility(ctx, s.userRepo, userID)
 	}
 
-	review := &models.Review{
-		BookID:  bookID,
-		Rating:  req.Rating,
-		Comment: req.Comment,
+	// each read-through gets its own review; books never started keep one review
+	progress, err := s.progressRepo.GetByBookID(ctx, bookID)
+	if err == nil && progress != nil {
+		existing, _ := s.repo.GetReviewByReadThrough(ctx, progress.ID)
+		if existing != nil {
+			return errors.New("you have already reviewed this read of the book")
+		}
+		review.ReadingProgressID = &progress.ID
+	} else {
+		existing, _ := s.repo.GetReviewByBookID(ctx, bookID)
+		if existing != nil {
+			return errors.New("you have already reviewed this book")
+		}
+	}
+
+	if err := s.repo.CreateReview(ctx, review); err != nil {
+		return err
+	}
+	metrics.ReviewsPosted.Inc()
+	return nil
+}
+
+func (s *reviewService) UpdateReview(ctx context.Context, userID uint, bookID uint, reviewID uint, req dto.UpdateReviewRequest) (*models.Review, error) {
+	review, err := s.ownReview(ctx, userID, bookID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	// visibility and the spoiler flag are settings, not part of the text history
+	if req.Visibility != "" {
+		review.Visibility = req.Visibility
+	}
+	if req.IsSpoiler != nil {
+		review.IsSpoiler = *req.IsSpoiler
+	}
+	if review.Rating == req.Rating && review.Comment == req.Comment {
+		if err := s.repo.UpdateReview(ctx, review, nil); err != nil {
+			return nil, err
+		}
+		return review, nil
 	}
 
-	return s.repo.CreateReview(review)
+	written := review.CreatedAt
+	if review.EditedAt != nil {
+		written = *review.EditedAt
+	}
+	previous := &models.ReviewRevision{
+		ReviewID:  review.ID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		WrittenAt: written,
+	}
+
+	now := time.Now()
+	review.Rating = req.Rating
+	review.Comment = req.Comment
+	review.EditedAt = &now
+	if err := s.repo.UpdateReview(ctx, review, previous); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, userID uint, bookID uint, reviewID uint) error {
+	review, err := s.ownReview(ctx, userID, bookID, reviewID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteReview(ctx, review.ID)
+}
+
+func (s *reviewService) GetReviewHistory(ctx context.Context, userID uint, bookID uint, reviewID uint) ([]models.ReviewRevision, error) {
+	review, err := s.ownReview(ctx, userID, bookID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetReviewHistory(ctx, review.ID)
+}
+
+// ownReview loads a review only if it was written for one of the user's books.
+func (s *reviewService) ownReview(ctx context.Context, userID uint, bookID uint, reviewID uint) (*models.Review, error) {
+	if _, err := s.bookRepo.GetBookByID(ctx, bookID, userID); err != nil {
+		return nil, ErrReviewAccessDenied
+	}
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil || review.BookID != bookID || review.UserID != userID {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
 }
 
-func (s *reviewService) GetBookReviews(userID uint, bookID uint) ([]models.Review, error) {
+// GetBookReviews is the community feed for a book: every reader's review of
+// the same work the viewer may see, with rating aggregates, one page at a time.
+// Other readers' spoilers come back without their text unless asked for.
+func (s *reviewService) GetBookReviews(ctx context.Context, userID uint, bookID uint, query dto.ReviewFeedQuery) (*dto.ReviewFeed, error) {
+
+	book, err := s.bookRepo.GetBookByID(ctx, bookID, userID)
+	if err != nil {
+		return nil, ErrReviewAccessDenied
+	}
+
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultReviewPageSize
+	}
+	sort := query.Sort
+	if sort == "" {
+		sort = defaultReviewSort
+	}
 
-	book, err := s.bookRepo.GetBookByID(bookID, userID)
+	scope := repository.ReviewScope{WorkID: book.WorkID, BookID: book.ID, ViewerID: userID}
+	summary, err := s.repo.GetReviewSummary(ctx, scope)
 	if err != nil {
-		return nil, errors.New("access denied")
+		return nil, err
 	}
 
-	if book.ISBN != "" {
-		return s.repo.GetReviewsByISBN(book.ISBN)
+	// fetch one extra row to know whether another page exists
+	reviews, err := s.repo.GetReviewFeed(ctx, scope, sort, limit+1, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := len(reviews) > limit
+	if hasMore {
+		reviews = reviews[:limit]
+	}
+	if reviews == nil {
+		reviews = []dto.PublicReview{}
+	}
+	for i := range reviews {
+		if reviews[i].IsSpoiler && !reviews[i].IsMine && !query.ShowSpoilers {
+			reviews[i].Comment = ""
+			reviews[i].SpoilerHidden = true
+		}
 	}
 
-	return s.repo.GetReviewsByBookID(bookID)
+	return &dto.ReviewFeed{Summary: summary, Reviews: reviews, Page: page, Limit: limit, HasMore: hasMore}, nil
+}
+
+// GetMyReviews lists everything the user has reviewed, newest first.
+func (s *reviewService) GetMyReviews(ctx context.Context, userID uint) ([]dto.MyReview, error) {
+	reviews, err := s.repo.GetReviewsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.MyReview, 0, len(reviews))
+	for i := len(reviews) - 1; i >= 0; i-- {
+		r := reviews[i]
+		result = append(result, dto.MyReview{
+			ID:            r.ID,
+			Rating:        r.Rating,
+			Comment:       r.Comment,
+			ReadThroughID: r.ReadingProgressID,
+			Visibility:    r.Visibility,
+			IsSpoiler:     r.IsSpoiler,
+			CreatedAt:     r.CreatedAt,
+			EditedAt:      r.EditedAt,
+			Book: dto.ReviewedBook{
+				ID:     r.Book.ID,
+				Title:  r.Book.Title,
+				Author: r.Book.Author,
+				ISBN:   r.Book.ISBN,
+				WorkID: r.Book.WorkID,
+			},
+		})
+	}
+	return result, nil
+}
+
+func (s *reviewService) MarkHelpful(ctx context.Context, userID uint, reviewID uint) error {
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil || !s.canView(ctx, review, userID) {
+		return ErrReviewNotFound
+	}
+	if review.UserID == userID {
+		return ErrOwnReviewVote
+	}
+	return s.repo.AddVote(ctx, &models.ReviewVote{ReviewID: reviewID, UserID: userID})
+}
+
+func (s *reviewService) UnmarkHelpful(ctx context.Context, userID uint, reviewID uint) error {
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil || !s.canView(ctx, review, userID) {
+		return ErrReviewNotFound
+	}
+	return s.repo.RemoveVote(ctx, reviewID, userID)
+}
+
+// defaultReviewVisibility is the visibility of a review the user did not pick
+// one for.
+func defaultReviewVisibility(ctx context.Context, users repository.UserRepository, userID uint) models.ReviewVisibility {
+	if user, err := users.FindByID(ctx, userID); err == nil && user != nil && user.DefaultReviewVisibility.Valid() {
+		return user.DefaultReviewVisibility
+	}
+	return models.VisibilityPublic
+}
+
+// canView applies the review's visibility to a single viewer.
+func (s *reviewService) canView(ctx context.Context, review *models.Review, viewerID uint) bool {
+	switch {
+	case review.UserID == viewerID, review.Visibility == models.VisibilityPublic:
+		return true
+	case review.Visibility == models.VisibilityFollowers:
+		following, err := s.userRepo.IsFollowing(ctx, viewerID, review.UserID)
+		return err == nil && following
+	}
+	return false
 }
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 )
 
 type FakeBookRepoForReview struct {
@@ -13,7 +15,7 @@ type FakeBookRepoForReview struct {
 	MockBook     *models.Book
 }
 
-func (f *FakeBookRepoForReview) GetBookByID(id uint, uid uint) (*models.Book, error) {
+func (f *FakeBookRepoForReview) GetBookByID(ctx context.Context, id uint, uid uint) (*models.Book, error) {
 	if !f.UserOwnsBook {
 		return nil, errors.New("book not found")
 	}
@@ -23,56 +25,134 @@ func (f *FakeBookRepoForReview) GetBookByID(id uint, uid uint) (*models.Book, er
 	return &models.Book{ID: id, UserID: uid, ISBN: "978123", TotalPages: 300}, nil
 }
 
-func (f *FakeBookRepoForReview) CreateBook(b *models.Book) error                  { return nil }
-func (f *FakeBookRepoForReview) GetBooksByUserID(uid uint) ([]models.Book, error) { return nil, nil }
-func (f *FakeBookRepoForReview) UpdateBook(bid, uid uint, b *models.Book) error   { return nil }
-func (f *FakeBookRepoForReview) DeleteBook(id, uid uint) error                    { return nil }
-func (f *FakeBookRepoForReview) GetDashboardStats(userID uint) (dto.DashboardStats, error) {
+func (f *FakeBookRepoForReview) CreateBook(ctx context.Context, b *models.Book) error { return nil }
+func (f *FakeBookRepoForReview) GetBooksByUserID(ctx context.Context, uid uint) ([]models.Book, error) {
+	return nil, nil
+}
+func (f *FakeBookRepoForReview) GetBookWithHistory(ctx context.Context, id uint, uid uint) (*models.Book, error) {
+	return f.GetBookByID(ctx, id, uid)
+}
+func (f *FakeBookRepoForReview) GetReadHistories(ctx context.Context, uid uint, bookIDs []uint) ([]models.ReadingProgress, error) {
+	return nil, nil
+}
+func (f *FakeBookRepoForReview) ListBooks(ctx context.Context, uid uint, q dto.BookListQuery) ([]models.Book, string, error) {
+	return nil, "", nil
+}
+func (f *FakeBookRepoForReview) UpdateBook(ctx context.Context, bid, uid uint, b *models.Book) error {
+	return nil
+}
+func (f *FakeBookRepoForReview) DeleteBook(ctx context.Context, id, uid uint) error { return nil }
+func (f *FakeBookRepoForReview) GetDashboardStats(ctx context.Context, userID uint) (dto.DashboardStats, error) {
 	return dto.DashboardStats{}, nil
 }
-func (f *FakeBookRepoForReview) SearchBooks(userID uint, query string) ([]models.Book, error) {
+func (f *FakeBookRepoForReview) SearchBooks(ctx context.Context, userID uint, query string) ([]models.Book, error) {
 	return []models.Book{}, nil
 }
-func (f *FakeBookRepoForReview) FindDuplicate(userID uint, title string, author string, isbn string) (*models.Book, error) {
+func (f *FakeBookRepoForReview) FindDuplicate(ctx context.Context, userID uint, title string, author string, isbn string) (*models.Book, error) {
 	return nil, nil
 }
 
 type FakeReviewRepo struct {
 	Reviews     []models.Review
 	SavedReview *models.Review
+	Feed        []dto.PublicReview
+	Summary     dto.ReviewSummary
+	Votes       []models.ReviewVote
+	Revisions   []models.ReviewRevision
+	Deleted     []uint
+	LastScope   repository.ReviewScope
+	LastLimit   int
+	LastOffset  int
 }
 
-func (f *FakeReviewRepo) CreateReview(r *models.Review) error {
+func (f *FakeReviewRepo) CreateReview(ctx context.Context, r *models.Review) error {
 	f.SavedReview = r
 	f.Reviews = append(f.Reviews, *r)
 	return nil
 }
 
-func (f *FakeReviewRepo) GetReviewsByBookID(bookID uint) ([]models.Review, error) {
-	return f.Reviews, nil
+func (f *FakeReviewRepo) GetReviewByBookID(ctx context.Context, bookID uint) (*models.Review, error) {
+	for _, r := range f.Reviews {
+		if r.BookID == bookID {
+			return &r, nil
+		}
+	}
+	return nil, nil
 }
 
-func (f *FakeReviewRepo) GetReviewByBookID(bookID uint) (*models.Review, error) {
+func (f *FakeReviewRepo) GetReviewByReadThrough(ctx context.Context, readThroughID uint) (*models.Review, error) {
 	for _, r := range f.Reviews {
-		if r.BookID == bookID {
+		if r.ReadingProgressID != nil && *r.ReadingProgressID == readThroughID {
 			return &r, nil
 		}
 	}
 	return nil, nil
 }
 
-func (f *FakeReviewRepo) GetReviewsByISBN(isbn string) ([]models.Review, error) {
+func (f *FakeReviewRepo) GetReviewByID(ctx context.Context, id uint) (*models.Review, error) {
+	for _, r := range f.Reviews {
+		if r.ID == id {
+			return &r, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *FakeReviewRepo) GetReviewSummary(ctx context.Context, scope repository.ReviewScope) (dto.ReviewSummary, error) {
+	return f.Summary, nil
+}
 
+func (f *FakeReviewRepo) GetReviewFeed(ctx context.Context, scope repository.ReviewScope, sort string, limit int, offset int) ([]dto.PublicReview, error) {
+	f.LastScope, f.LastLimit, f.LastOffset = scope, limit, offset
+	if offset >= len(f.Feed) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(f.Feed) {
+		end = len(f.Feed)
+	}
+	return f.Feed[offset:end], nil
+}
+
+func (f *FakeReviewRepo) UpdateReview(ctx context.Context, review *models.Review, previous *models.ReviewRevision) error {
+	f.SavedReview = review
+	if previous != nil {
+		f.Revisions = append(f.Revisions, *previous)
+	}
+	return nil
+}
+
+func (f *FakeReviewRepo) DeleteReview(ctx context.Context, id uint) error {
+	f.Deleted = append(f.Deleted, id)
+	return nil
+}
+
+func (f *FakeReviewRepo) GetReviewHistory(ctx context.Context, reviewID uint) ([]models.ReviewRevision, error) {
+	return f.Revisions, nil
+}
+
+func (f *FakeReviewRepo) AddVote(ctx context.Context, vote *models.ReviewVote) error {
+	f.Votes = append(f.Votes, *vote)
+	return nil
+}
+
+func (f *FakeReviewRepo) RemoveVote(ctx context.Context, reviewID uint, userID uint) error {
+	f.Votes = nil
+	return nil
+}
+
+func (f *FakeReviewRepo) GetReviewsByUserID(ctx context.Context, userID uint) ([]models.Review, error) {
 	return f.Reviews, nil
 }
 
 func TestAddReview_BookNotFound(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForReview{UserOwnsBook: false}
 	reviewRepo := &FakeReviewRepo{}
-	service := NewReviewService(reviewRepo, bookRepo)
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
 
 	req := dto.CreateReviewRequest{Rating: 5, Comment: "Great!"}
-	err := service.AddReview(1, 99, req)
+	err := service.AddReview(ctx, 1, 99, req)
 
 	if err == nil {
 		t.Errorf("Expected 'book not found' error, but got nil")
@@ -80,12 +160,13 @@ func TestAddReview_BookNotFound(t *testing.T) {
 }
 
 func TestAddReview_Success(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
 	reviewRepo := &FakeReviewRepo{}
-	service := NewReviewService(reviewRepo, bookRepo)
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
 
 	req := dto.CreateReviewRequest{Rating: 4, Comment: "Good read"}
-	err := service.AddReview(1, 10, req)
+	err := service.AddReview(ctx, 1, 10, req)
 
 	if err != nil {
 		t.Errorf("Expected success, but got error: %v", err)
@@ -93,42 +174,359 @@ func TestAddReview_Success(t *testing.T) {
 	if reviewRepo.SavedReview.Rating != 4 {
 		t.Errorf("Expected saved rating 4, but got %d", reviewRepo.SavedReview.Rating)
 	}
+	if reviewRepo.SavedReview.UserID != 1 {
+		t.Errorf("Expected review author 1, but got %d", reviewRepo.SavedReview.UserID)
+	}
 }
 
 func TestGetBookReviews_Success(t *testing.T) {
-
+	ctx := context.Background()
+	workID := uint(3)
 	bookRepo := &FakeBookRepoForReview{
 		UserOwnsBook: true,
-		MockBook:     &models.Book{ID: 10, ISBN: "978123"},
+		MockBook:     &models.Book{ID: 10, WorkID: &workID},
 	}
 
 	reviewRepo := &FakeReviewRepo{
-		Reviews: []models.Review{{Rating: 5, Comment: "Community Review"}},
+		Feed:    []dto.PublicReview{{ID: 1, Rating: 5, Comment: "Community Review", ReviewerName: "Ana"}},
+		Summary: dto.ReviewSummary{AverageRating: 5, RatingCount: 1},
 	}
-	service := NewReviewService(reviewRepo, bookRepo)
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
 
-	reviews, err := service.GetBookReviews(1, 10)
+	feed, err := service.GetBookReviews(ctx, 1, 10, dto.ReviewFeedQuery{})
 
 	if err != nil {
 		t.Errorf("Expected success, but got error: %v", err)
 	}
-	if len(reviews) == 0 {
-		t.Errorf("Expected to get reviews, but list was empty")
+	if len(feed.Reviews) == 0 || feed.Summary.RatingCount != 1 {
+		t.Errorf("Expected to get reviews, but got %+v", feed)
+	}
+	if reviewRepo.LastScope.WorkID == nil || *reviewRepo.LastScope.WorkID != 3 {
+		t.Errorf("Expected the feed to cover the whole work")
+	}
+}
+
+func TestGetBookReviews_Pagination(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	feed := make([]dto.PublicReview, 5)
+	reviewRepo := &FakeReviewRepo{Feed: feed}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	first, err := service.GetBookReviews(ctx, 1, 10, dto.ReviewFeedQuery{Page: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(first.Reviews) != 2 || !first.HasMore {
+		t.Errorf("Expected 2 reviews and more pages, got %d / %v", len(first.Reviews), first.HasMore)
+	}
+
+	last, _ := service.GetBookReviews(ctx, 1, 10, dto.ReviewFeedQuery{Page: 3, Limit: 2})
+	if len(last.Reviews) != 1 || last.HasMore || reviewRepo.LastOffset != 4 {
+		t.Errorf("Expected the last page to hold 1 review, got %d / %v", len(last.Reviews), last.HasMore)
+	}
+}
+
+func TestMarkHelpful_OwnReview(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 1}},
+	}
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	err := service.MarkHelpful(ctx, 1, 5)
+	if !errors.Is(err, ErrOwnReviewVote) {
+		t.Errorf("Expected ErrOwnReviewVote, got %v", err)
+	}
+}
+
+func TestMarkHelpful_Success(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 2, Visibility: models.VisibilityPublic}},
+	}
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	if err := service.MarkHelpful(ctx, 1, 5); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(reviewRepo.Votes) != 1 || reviewRepo.Votes[0].UserID != 1 {
+		t.Errorf("Expected a vote from user 1, got %+v", reviewRepo.Votes)
+	}
+}
+
+func TestMarkHelpful_NotFound(t *testing.T) {
+	ctx := context.Background()
+	service := NewReviewService(&FakeReviewRepo{}, &FakeBookRepoForReview{}, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	err := service.MarkHelpful(ctx, 1, 99)
+	if !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected ErrReviewNotFound, got %v", err)
 	}
 }
 
 func TestAddReview_DuplicateCheck(t *testing.T) {
+	ctx := context.Background()
 	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
 	existingReview := models.Review{BookID: 10, Rating: 5, Comment: "Already exists"}
 	reviewRepo := &FakeReviewRepo{
 		Reviews: []models.Review{existingReview},
 	}
-	service := NewReviewService(reviewRepo, bookRepo)
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
 
 	req := dto.CreateReviewRequest{Rating: 1, Comment: "Spam"}
-	err := service.AddReview(1, 10, req)
+	err := service.AddReview(ctx, 1, 10, req)
 
 	if err == nil {
 		t.Errorf("Expected error for duplicate review, but got nil")
 	}
 }
+
+func TestAddReview_RereadGetsOwnReview(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	firstRead := uint(1)
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{BookID: 10, ReadingProgressID: &firstRead, Rating: 3}},
+	}
+	progressRepo := &FakeProgressRepo{
+		SavedData: &models.ReadingProgress{ID: 2, BookID: 10, ReadNumber: 2, IsCurrent: true, Status: "Finished"},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, progressRepo, &FakeUserRepo{})
+
+	err := service.AddReview(ctx, 1, 10, dto.CreateReviewRequest{Rating: 5, Comment: "Better the second time"})
+	if err != nil {
+		t.Fatalf("Expected re-read review to be allowed, but got error: %v", err)
+	}
+	if reviewRepo.SavedReview.ReadingProgressID == nil || *reviewRepo.SavedReview.ReadingProgressID != 2 {
+		t.Errorf("Expected the review to be linked to read-through 2")
+	}
+}
+
+func TestUpdateReview_KeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 1, Rating: 3, Comment: "Slow start"}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	review, err := service.UpdateReview(ctx, 1, 10, 5, dto.UpdateReviewRequest{Rating: 5, Comment: "Loved it on the re-read"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if review.Rating != 5 || review.EditedAt == nil {
+		t.Errorf("Expected an edited 5-star review, got %+v", review)
+	}
+	if len(reviewRepo.Revisions) != 1 || reviewRepo.Revisions[0].Rating != 3 || reviewRepo.Revisions[0].Comment != "Slow start" {
+		t.Errorf("Expected the old version in history, got %+v", reviewRepo.Revisions)
+	}
+}
+
+func TestUpdateReview_NoChangeNoRevision(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 1, Rating: 4, Comment: "Good"}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	review, err := service.UpdateReview(ctx, 1, 10, 5, dto.UpdateReviewRequest{Rating: 4, Comment: "Good"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if review.EditedAt != nil || len(reviewRepo.Revisions) != 0 {
+		t.Errorf("Expected no edit to be recorded")
+	}
+}
+
+func TestUpdateReview_OtherBooksReview(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 11, Rating: 4}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	_, err := service.UpdateReview(ctx, 1, 10, 5, dto.UpdateReviewRequest{Rating: 1})
+	if !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestDeleteReview_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: false}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	err := service.DeleteReview(ctx, 2, 10, 5)
+	if !errors.Is(err, ErrReviewAccessDenied) {
+		t.Errorf("Expected ErrReviewAccessDenied, got %v", err)
+	}
+	if len(reviewRepo.Deleted) != 0 {
+		t.Errorf("Expected nothing deleted")
+	}
+}
+
+func TestDeleteReview_Success(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 1}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	if err := service.DeleteReview(ctx, 1, 10, 5); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(reviewRepo.Deleted) != 1 || reviewRepo.Deleted[0] != 5 {
+		t.Errorf("Expected review 5 deleted, got %v", reviewRepo.Deleted)
+	}
+}
+
+func TestGetMyReviews_NewestFirstWithBook(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{
+			{ID: 1, BookID: 10, UserID: 1, Rating: 3, Book: models.Book{ID: 10, Title: "Dune"}},
+			{ID: 2, BookID: 11, UserID: 1, Rating: 5, Book: models.Book{ID: 11, Title: "The Hobbit"}},
+		},
+	}
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	reviews, err := service.GetMyReviews(ctx, 1)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(reviews) != 2 || reviews[0].ID != 2 || reviews[0].Book.Title != "The Hobbit" {
+		t.Errorf("Expected newest review first with its book, got %+v", reviews)
+	}
+}
+
+func TestAddReview_UsesDefaultVisibility(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{}
+	userRepo := &FakeUserRepo{Users: []models.User{{ID: 1, DefaultReviewVisibility: models.VisibilityFollowers}}}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, userRepo)
+
+	if err := service.AddReview(ctx, 1, 10, dto.CreateReviewRequest{Rating: 4}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if reviewRepo.SavedReview.Visibility != models.VisibilityFollowers {
+		t.Errorf("Expected the user's default visibility, got %q", reviewRepo.SavedReview.Visibility)
+	}
+
+	if err := service.AddReview(ctx, 1, 11, dto.CreateReviewRequest{Rating: 4, Visibility: models.VisibilityPrivate}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if reviewRepo.SavedReview.Visibility != models.VisibilityPrivate {
+		t.Errorf("Expected the requested visibility, got %q", reviewRepo.SavedReview.Visibility)
+	}
+}
+
+func TestGetBookReviews_HidesSpoilers(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Feed: []dto.PublicReview{
+			{ID: 1, Comment: "The butler did it", IsSpoiler: true},
+			{ID: 2, Comment: "My own spoiler", IsSpoiler: true, IsMine: true},
+		},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	feed, err := service.GetBookReviews(ctx, 1, 10, dto.ReviewFeedQuery{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if feed.Reviews[0].Comment != "" || !feed.Reviews[0].SpoilerHidden {
+		t.Errorf("Expected the spoiler to be hidden, got %+v", feed.Reviews[0])
+	}
+	if feed.Reviews[1].Comment == "" || feed.Reviews[1].SpoilerHidden {
+		t.Errorf("Expected own spoilers to stay visible, got %+v", feed.Reviews[1])
+	}
+	if reviewRepo.LastScope.ViewerID != 1 {
+		t.Errorf("Expected the feed to be scoped to the viewer, got %d", reviewRepo.LastScope.ViewerID)
+	}
+
+	reviewRepo.Feed[0].Comment = "The butler did it"
+	shown, _ := service.GetBookReviews(ctx, 1, 10, dto.ReviewFeedQuery{ShowSpoilers: true})
+	if shown.Reviews[0].Comment != "The butler did it" {
+		t.Errorf("Expected show_spoilers to reveal the comment, got %+v", shown.Reviews[0])
+	}
+}
+
+func TestUpdateReview_KeepsVisibilityWhenOmitted(t *testing.T) {
+	ctx := context.Background()
+	bookRepo := &FakeBookRepoForReview{UserOwnsBook: true}
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 1, Rating: 4, Comment: "Good", Visibility: models.VisibilityPrivate}},
+	}
+	service := NewReviewService(reviewRepo, bookRepo, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	spoiler := true
+	review, err := service.UpdateReview(ctx, 1, 10, 5, dto.UpdateReviewRequest{Rating: 4, Comment: "Good", IsSpoiler: &spoiler})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if review.Visibility != models.VisibilityPrivate || !review.IsSpoiler {
+		t.Errorf("Expected a private spoiler, got %+v", review)
+	}
+	if review.EditedAt != nil || len(reviewRepo.Revisions) != 0 {
+		t.Errorf("Expected a settings change not to count as an edit")
+	}
+}
+
+func TestMarkHelpful_FollowersOnly(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 2, Visibility: models.VisibilityFollowers}},
+	}
+	userRepo := &FakeUserRepo{}
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, userRepo)
+
+	if err := service.MarkHelpful(ctx, 1, 5); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected a hidden review to look missing, got %v", err)
+	}
+
+	userRepo.Follows = []models.Follow{{FollowerID: 1, FolloweeID: 2, Approved: true}}
+	if err := service.MarkHelpful(ctx, 1, 5); err != nil {
+		t.Errorf("Expected followers to vote, got %v", err)
+	}
+}
+
+func TestUnmarkHelpful_HiddenReview(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 2, Visibility: models.VisibilityPrivate}},
+	}
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, &FakeUserRepo{})
+
+	if err := service.UnmarkHelpful(ctx, 1, 5); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected a hidden review to look missing, got %v", err)
+	}
+	if err := service.UnmarkHelpful(ctx, 2, 5); err != nil {
+		t.Errorf("Expected the author to see their own review, got %v", err)
+	}
+}
+
+func TestMarkHelpful_UnapprovedFollowCannotSeeFollowersOnly(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := &FakeReviewRepo{
+		Reviews: []models.Review{{ID: 5, BookID: 10, UserID: 2, Visibility: models.VisibilityFollowers}},
+	}
+	userRepo := &FakeUserRepo{Users: []models.User{{ID: 1}, {ID: 2}}}
+	users := NewUserService(userRepo, &FakeTokenRepo{}, testJWT)
+	service := NewReviewService(reviewRepo, &FakeBookRepoForReview{}, &FakeProgressRepo{}, userRepo)
+
+	if err := users.Follow(ctx, 1, 2); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if err := service.MarkHelpful(ctx, 1, 5); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected a one-sided follow to keep the review hidden, got %v", err)
+	}
+}
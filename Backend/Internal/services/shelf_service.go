@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+)
+
+var (
+	ErrShelfNotFound  = errors.New("shelf not found")
+	ErrShelfExists    = errors.New("you already have a shelf with this name")
+	ErrInvalidShelf   = errors.New("shelf name cannot be empty")
+	ErrBooksNotOwned  = errors.New("one or more books are not in your library")
+	ErrInvalidTagName = errors.New("tag names cannot be empty")
+)
+
+type ShelfService interface {
+	CreateShelf(ctx context.Context, userID uint, req dto.ShelfRequest) (*models.Shelf, error)
+	ListShelves(ctx context.Context, userID uint) ([]dto.ShelfSummary, error)
+	GetShelf(ctx context.Context, userID uint, shelfID uint) (*models.Shelf, error)
+	UpdateShelf(ctx context.Context, userID uint, shelfID uint, req dto.ShelfRequest) (*models.Shelf, error)
+	DeleteShelf(ctx context.Context, userID uint, shelfID uint) error
+	AddBooks(ctx context.Context, userID uint, shelfID uint, bookIDs []uint) error
+	RemoveBooks(ctx context.Context, userID uint, shelfID uint, bookIDs []uint) error
+
+	ListTags(ctx context.Context, userID uint) ([]dto.TagSummary, error)
+	TagBooks(ctx context.Context, userID uint, req dto.TagBooksRequest) error
+	UntagBooks(ctx context.Context, userID uint, req dto.TagBooksRequest) error
+}
+
+type shelfService struct {
+	repo repository.ShelfRepository
+}
+
+func NewShelfService(repo repository.ShelfRepository) ShelfService {
+	return &shelfService{repo: repo}
+}
+
+func (s *shelfService) CreateShelf(ctx context.Context, userID uint, req dto.ShelfRequest) (*models.Shelf, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidShelf
+	}
+	if _, err := s.repo.FindShelfByName(ctx, userID, name); err == nil {
+		return nil, ErrShelfExists
+	}
+
+	shelf := &models.Shelf{UserID: userID, Name: name, Description: strings.TrimSpace(req.Description)}
+	if err := s.repo.CreateShelf(ctx, shelf); err != nil {
+		return nil, err
+	}
+	return shelf, nil
+}
+
+func (s *shelfService) ListShelves(ctx context.Context, userID uint) ([]dto.ShelfSummary, error) {
+	return s.repo.GetShelvesByUserID(ctx, userID)
+}
+
+func (s *shelfService) GetShelf(ctx context.Context, userID uint, shelfID uint) (*models.Shelf, error) {
+	shelf, err := s.repo.GetShelf(ctx, shelfID, userID)
+	if err != nil {
+		return nil, ErrShelfNotFound
+	}
+	return shelf, nil
+}
+
+func (s *shelfService) UpdateShelf(ctx context.Context, userID uint, shelfID uint, req dto.ShelfRequest) (*models.Shelf, error) {
+	shelf, err := s.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidShelf
+	}
+	if existing, err := s.repo.FindShelfByName(ctx, userID, name); err == nil && existing.ID != shelf.ID {
+		return nil, ErrShelfExists
+	}
+
+	shelf.Name = name
+	shelf.Description = strings.TrimSpace(req.Description)
+	if err := s.repo.UpdateShelf(ctx, shelf); err != nil {
+		return nil, err
+	}
+	return shelf, nil
+}
+
+func (s *shelfService) DeleteShelf(ctx context.Context, userID uint, shelfID uint) error {
+	shelf, err := s.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteShelf(ctx, shelf)
+}
+
+func (s *shelfService) AddBooks(ctx context.Context, userID uint, shelfID uint, bookIDs []uint) error {
+	shelf, books, err := s.shelfAndBooks(ctx, userID, shelfID, bookIDs)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddBooksToShelf(ctx, shelf, books)
+}
+
+func (s *shelfService) RemoveBooks(ctx context.Context, userID uint, shelfID uint, bookIDs []uint) error {
+	shelf, books, err := s.shelfAndBooks(ctx, userID, shelfID, bookIDs)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveBooksFromShelf(ctx, shelf, books)
+}
+
+func (s *shelfService) ListTags(ctx context.Context, userID uint) ([]dto.TagSummary, error) {
+	return s.repo.GetTagsByUserID(ctx, userID)
+}
+
+func (s *shelfService) TagBooks(ctx context.Context, userID uint, req dto.TagBooksRequest) error {
+	names, err := normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	books, err := s.ownedBooks(ctx, userID, req.BookIDs)
+	if err != nil {
+		return err
+	}
+	tags, err := s.repo.FindOrCreateTags(ctx, userID, names)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddTagsToBooks(ctx, books, tags)
+}
+
+func (s *shelfService) UntagBooks(ctx context.Context, userID uint, req dto.TagBooksRequest) error {
+	names, err := normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	books, err := s.ownedBooks(ctx, userID, req.BookIDs)
+	if err != nil {
+		return err
+	}
+	tags, err := s.repo.GetTagsByNames(ctx, userID, names)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return s.repo.RemoveTagsFromBooks(ctx, books, tags)
+}
+
+func (s *shelfService) shelfAndBooks(ctx context.Context, userID uint, shelfID uint, bookIDs []uint) (*models.Shelf, []models.Book, error) {
+	shelf, err := s.GetShelf(ctx, userID, shelfID)
+	if err != nil {
+		return nil, nil, err
+	}
+	books, err := s.ownedBooks(ctx, userID, bookIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shelf, books, nil
+}
+
+// ownedBooks loads the requested books and fails the whole batch if any of
+// them belongs to someone else or does not exist.
+func (s *shelfService) ownedBooks(ctx context.Context, userID uint, bookIDs []uint) ([]models.Book, error) {
+	unique := make([]uint, 0, len(bookIDs))
+	seen := make(map[uint]bool, len(bookIDs))
+	for _, id := range bookIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	books, err := s.repo.GetBooksByIDs(ctx, userID, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(books) != len(unique) {
+		return nil, ErrBooksNotOwned
+	}
+	return books, nil
+}
+
+// normalizeTags trims and lower-cases tag names and drops duplicates.
+func normalizeTags(raw []string) ([]string, error) {
+	names := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		name := strings.ToLower(strings.TrimSpace(r))
+		if name == "" {
+			return nil, ErrInvalidTagName
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
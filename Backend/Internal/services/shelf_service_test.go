@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+)
+
+type FakeShelfRepo struct {
+	Shelves    []models.Shelf
+	Books      []models.Book
+	Tags       []models.Tag
+	ShelfBooks map[uint][]uint
+	BookTags   map[uint][]string
+}
+
+func (f *FakeShelfRepo) CreateShelf(ctx context.Context, shelf *models.Shelf) error {
+	shelf.ID = uint(len(f.Shelves) + 1)
+	f.Shelves = append(f.Shelves, *shelf)
+	return nil
+}
+
+func (f *FakeShelfRepo) GetShelvesByUserID(ctx context.Context, userID uint) ([]dto.ShelfSummary, error) {
+	var result []dto.ShelfSummary
+	for _, s := range f.Shelves {
+		if s.UserID == userID {
+			result = append(result, dto.ShelfSummary{ID: s.ID, Name: s.Name, BookCount: int64(len(f.ShelfBooks[s.ID]))})
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeShelfRepo) GetShelf(ctx context.Context, id uint, userID uint) (*models.Shelf, error) {
+	for i := range f.Shelves {
+		if f.Shelves[i].ID == id && f.Shelves[i].UserID == userID {
+			return &f.Shelves[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *FakeShelfRepo) FindShelfByName(ctx context.Context, userID uint, name string) (*models.Shelf, error) {
+	for i := range f.Shelves {
+		if f.Shelves[i].UserID == userID && f.Shelves[i].Name == name {
+			return &f.Shelves[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *FakeShelfRepo) UpdateShelf(ctx context.Context, shelf *models.Shelf) error { return nil }
+
+func (f *FakeShelfRepo) DeleteShelf(ctx context.Context, shelf *models.Shelf) error {
+	delete(f.ShelfBooks, shelf.ID)
+	return nil
+}
+
+func (f *FakeShelfRepo) AddBooksToShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error {
+	if f.ShelfBooks == nil {
+		f.ShelfBooks = map[uint][]uint{}
+	}
+	for _, b := range books {
+		f.ShelfBooks[shelf.ID] = append(f.ShelfBooks[shelf.ID], b.ID)
+	}
+	return nil
+}
+
+func (f *FakeShelfRepo) RemoveBooksFromShelf(ctx context.Context, shelf *models.Shelf, books []models.Book) error {
+	f.ShelfBooks[shelf.ID] = nil
+	return nil
+}
+
+func (f *FakeShelfRepo) GetBooksByIDs(ctx context.Context, userID uint, ids []uint) ([]models.Book, error) {
+	var result []models.Book
+	for _, b := range f.Books {
+		for _, id := range ids {
+			if b.ID == id && b.UserID == userID {
+				result = append(result, b)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeShelfRepo) GetTagsByUserID(ctx context.Context, userID uint) ([]dto.TagSummary, error) {
+	return nil, nil
+}
+
+func (f *FakeShelfRepo) FindOrCreateTags(ctx context.Context, userID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	for _, n := range names {
+		tag := models.Tag{ID: uint(len(f.Tags) + 1), UserID: userID, Name: n}
+		f.Tags = append(f.Tags, tag)
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (f *FakeShelfRepo) GetTagsByNames(ctx context.Context, userID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	for _, t := range f.Tags {
+		for _, n := range names {
+			if t.UserID == userID && t.Name == n {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags, nil
+}
+
+func (f *FakeShelfRepo) AddTagsToBooks(ctx context.Context, books []models.Book, tags []models.Tag) error {
+	if f.BookTags == nil {
+		f.BookTags = map[uint][]string{}
+	}
+	for _, b := range books {
+		for _, t := range tags {
+			f.BookTags[b.ID] = append(f.BookTags[b.ID], t.Name)
+		}
+	}
+	return nil
+}
+
+func (f *FakeShelfRepo) RemoveTagsFromBooks(ctx context.Context, books []models.Book, tags []models.Tag) error {
+	for _, b := range books {
+		f.BookTags[b.ID] = nil
+	}
+	return nil
+}
+
+func TestCreateShelf_Success(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{}
+	service := NewShelfService(repo)
+
+	shelf, err := service.CreateShelf(ctx, 1, dto.ShelfRequest{Name: "  Book Club  "})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if shelf.Name != "Book Club" || shelf.UserID != 1 {
+		t.Errorf("Got %+v", shelf)
+	}
+}
+
+func TestCreateShelf_DuplicateName(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{Shelves: []models.Shelf{{ID: 1, UserID: 1, Name: "To Buy"}}}
+	service := NewShelfService(repo)
+
+	_, err := service.CreateShelf(ctx, 1, dto.ShelfRequest{Name: "To Buy"})
+	if !errors.Is(err, ErrShelfExists) {
+		t.Errorf("Expected ErrShelfExists, got %v", err)
+	}
+}
+
+func TestGetShelf_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{Shelves: []models.Shelf{{ID: 1, UserID: 2, Name: "Work"}}}
+	service := NewShelfService(repo)
+
+	_, err := service.GetShelf(ctx, 1, 1)
+	if !errors.Is(err, ErrShelfNotFound) {
+		t.Errorf("Expected ErrShelfNotFound, got %v", err)
+	}
+}
+
+func TestAddBooks_Success(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{
+		Shelves: []models.Shelf{{ID: 1, UserID: 1, Name: "Work"}},
+		Books:   []models.Book{{ID: 10, UserID: 1}, {ID: 11, UserID: 1}},
+	}
+	service := NewShelfService(repo)
+
+	if err := service.AddBooks(ctx, 1, 1, []uint{10, 11, 10}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if len(repo.ShelfBooks[1]) != 2 {
+		t.Errorf("Expected 2 books on the shelf, got %v", repo.ShelfBooks[1])
+	}
+}
+
+func TestAddBooks_NotOwned(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{
+		Shelves: []models.Shelf{{ID: 1, UserID: 1, Name: "Work"}},
+		Books:   []models.Book{{ID: 10, UserID: 1}, {ID: 11, UserID: 2}},
+	}
+	service := NewShelfService(repo)
+
+	err := service.AddBooks(ctx, 1, 1, []uint{10, 11})
+	if !errors.Is(err, ErrBooksNotOwned) {
+		t.Errorf("Expected ErrBooksNotOwned, got %v", err)
+	}
+	if len(repo.ShelfBooks[1]) != 0 {
+		t.Errorf("Expected nothing added, got %v", repo.ShelfBooks[1])
+	}
+}
+
+func TestTagBooks_NormalizesNames(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{Books: []models.Book{{ID: 10, UserID: 1}}}
+	service := NewShelfService(repo)
+
+	req := dto.TagBooksRequest{BookIDs: []uint{10}, Tags: []string{" Sci-Fi", "sci-fi", "Classics"}}
+	if err := service.TagBooks(ctx, 1, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	got := repo.BookTags[10]
+	if len(got) != 2 || got[0] != "sci-fi" || got[1] != "classics" {
+		t.Errorf("Got tags %v", got)
+	}
+}
+
+func TestTagBooks_EmptyTag(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeShelfRepo{Books: []models.Book{{ID: 10, UserID: 1}}}
+	service := NewShelfService(repo)
+
+	err := service.TagBooks(ctx, 1, dto.TagBooksRequest{BookIDs: []uint{10}, Tags: []string{"  "}})
+	if !errors.Is(err, ErrInvalidTagName) {
+		t.Errorf("Expected ErrInvalidTagName, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+)
+
+const (
+	defaultStatsDays = 365
+	maxStatsDays     = 5 * 366
+)
+
+var ErrInvalidStatsRange = errors.New("invalid date range: use from/to as YYYY-MM-DD, from before to, at most 5 years")
+
+type StatsService interface {
+	GetStats(ctx context.Context, userID uint, query dto.StatsQuery) (*dto.ReadingStats, error)
+}
+
+type statsService struct {
+	repo repository.StatsRepository
+}
+
+func NewStatsService(repo repository.StatsRepository) StatsService {
+	return &statsService{repo: repo}
+}
+
+// GetStats builds time-series, streaks and breakdowns from progress history.
+// Without a range it covers the last year up to today.
+func (s *statsService) GetStats(ctx context.Context, userID uint, query dto.StatsQuery) (*dto.ReadingStats, error) {
+	today := dayOf(time.Now())
+	from, to, err := statsRange(query, today)
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+
+	reads, err := s.repo.GetFinishedReads(ctx, userID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.repo.GetPagesPerDay(ctx, userID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	readingDays, err := s.repo.GetReadingDays(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	abandoned, err := s.repo.CountDidNotFinish(ctx, userID, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dto.ReadingStats{
+		From:          from.Format(dateLayout),
+		To:            to.Format(dateLayout),
+		BooksFinished: int64(len(reads)),
+		DidNotFinish:  abandoned,
+		Streaks:       readingStreaks(readingDays, today),
+	}
+	stats.BooksPerMonth = booksPerMonth(reads, from, to)
+	stats.PagesPerDay, stats.PagesPerWeek, stats.PagesRead = pagesSeries(days, from, to)
+	stats.AverageDaysToFinish = averageDaysToFinish(reads)
+	stats.Genres = breakdown(reads, func(r dto.FinishedRead) string { return r.Genre })
+	stats.Authors = breakdown(reads, func(r dto.FinishedRead) string { return r.Author })
+	return stats, nil
+}
+
+func statsRange(query dto.StatsQuery, today time.Time) (time.Time, time.Time, error) {
+	to := today
+	if query.To != "" {
+		parsed, err := time.Parse(dateLayout, query.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatsRange
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultStatsDays)
+	if query.From != "" {
+		parsed, err := time.Parse(dateLayout, query.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatsRange
+		}
+		from = parsed
+	}
+	if from.After(to) || to.Sub(from) > maxStatsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidStatsRange
+	}
+	return from, to, nil
+}
+
+// dayOf drops the time of day, keeping the calendar date.
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func booksPerMonth(reads []dto.FinishedRead, from time.Time, to time.Time) []dto.MonthlyBooks {
+	counts := map[string]int64{}
+	for _, r := range reads {
+		counts[r.FinishedAt.Format("2006-01")]++
+	}
+
+	var months []dto.MonthlyBooks
+	for m := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(to); m = m.AddDate(0, 1, 0) {
+		key := m.Format("2006-01")
+		months = append(months, dto.MonthlyBooks{Month: key, Books: counts[key]})
+	}
+	return months
+}
+
+// pagesSeries fills in every day of the range and rolls days up into ISO
+// weeks, which start on Monday.
+func pagesSeries(days []dto.ReadingDay, from time.Time, to time.Time) ([]dto.DailyPages, []dto.WeeklyPages, int64) {
+	pages := map[string]int64{}
+	for _, d := range days {
+		pages[d.Day.Format(dateLayout)] += d.Pages
+	}
+
+	var daily []dto.DailyPages
+	var weekly []dto.WeeklyPages
+	var total int64
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		n := pages[d.Format(dateLayout)]
+		daily = append(daily, dto.DailyPages{Date: d.Format(dateLayout), Pages: n})
+		total += n
+
+		weekStart := d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7)).Format(dateLayout)
+		if len(weekly) == 0 || weekly[len(weekly)-1].WeekStart != weekStart {
+			weekly = append(weekly, dto.WeeklyPages{WeekStart: weekStart})
+		}
+		weekly[len(weekly)-1].Pages += n
+	}
+	return daily, weekly, total
+}
+
+// readingStreaks counts consecutive reading days. The current streak is still
+// alive if the user read today or yesterday.
+func readingStreaks(days []time.Time, today time.Time) dto.ReadingStreaks {
+	var streaks dto.ReadingStreaks
+	if len(days) == 0 {
+		return streaks
+	}
+
+	run := 0
+	var prev time.Time
+	for i, d := range days {
+		d = dayOf(d)
+		if i > 0 && d.Equal(prev) {
+			continue
+		}
+		if i > 0 && d.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > streaks.Longest {
+			streaks.Longest = run
+		}
+		prev = d
+	}
+
+	streaks.LastReadOn = prev.Format(dateLayout)
+	if !prev.Before(today.AddDate(0, 0, -1)) {
+		streaks.Current = run
+	}
+	return streaks
+}
+
+func averageDaysToFinish(reads []dto.FinishedRead) *float64 {
+	var total float64
+	var n int
+	for _, r := range reads {
+		if r.StartedAt == nil || r.FinishedAt.Before(*r.StartedAt) {
+			continue
+		}
+		total += r.FinishedAt.Sub(*r.StartedAt).Hours() / 24
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	avg := math.Round(total/float64(n)*10) / 10
+	return &avg
+}
+
+// breakdown counts finished books per name, most read first.
+func breakdown(reads []dto.FinishedRead, name func(dto.FinishedRead) string) []dto.StatsBreakdown {
+	counts := map[string]int64{}
+	for _, r := range reads {
+		key := strings.TrimSpace(name(r))
+		if key == "" {
+			key = "Unknown"
+		}
+		counts[key]++
+	}
+
+	result := make([]dto.StatsBreakdown, 0, len(counts))
+	for k, v := range counts {
+		result = append(result, dto.StatsBreakdown{Name: k, Books: v})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Books != result[j].Books {
+			return result[i].Books > result[j].Books
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+)
+
+type FakeStatsRepo struct {
+	Reads       []dto.FinishedRead
+	Days        []dto.ReadingDay
+	ReadingDays []time.Time
+	Abandoned   int64
+	From, To    time.Time
+}
+
+func (f *FakeStatsRepo) GetFinishedReads(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.FinishedRead, error) {
+	f.From, f.To = start, end
+	return f.Reads, nil
+}
+
+func (f *FakeStatsRepo) GetPagesPerDay(ctx context.Context, userID uint, start time.Time, end time.Time) ([]dto.ReadingDay, error) {
+	return f.Days, nil
+}
+
+func (f *FakeStatsRepo) GetReadingDays(ctx context.Context, userID uint) ([]time.Time, error) {
+	return f.ReadingDays, nil
+}
+
+func (f *FakeStatsRepo) CountDidNotFinish(ctx context.Context, userID uint, start time.Time, end time.Time) (int64, error) {
+	return f.Abandoned, nil
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse(dateLayout, s)
+	return t
+}
+
+func TestGetStats_TimeSeries(t *testing.T) {
+	ctx := context.Background()
+	started := day("2024-01-01")
+	repo := &FakeStatsRepo{
+		Reads: []dto.FinishedRead{
+			{Author: "Le Guin", Genre: "Sci-Fi", StartedAt: &started, FinishedAt: day("2024-01-05")},
+			{Author: "Le Guin", Genre: "Fantasy", FinishedAt: day("2024-02-10")},
+			{Author: "Herbert", Genre: "Sci-Fi", FinishedAt: day("2024-02-20")},
+		},
+		Days: []dto.ReadingDay{
+			{Day: day("2024-01-01"), Pages: 30},
+			{Day: day("2024-01-08"), Pages: 20},
+		},
+	}
+	service := NewStatsService(repo)
+
+	stats, err := service.GetStats(ctx, 1, dto.StatsQuery{From: "2024-01-01", To: "2024-02-29"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if !repo.To.Equal(day("2024-03-01")) {
+		t.Errorf("Expected the range to include the last day, got %v", repo.To)
+	}
+	if len(stats.BooksPerMonth) != 2 || stats.BooksPerMonth[1].Books != 2 {
+		t.Errorf("Unexpected books per month %+v", stats.BooksPerMonth)
+	}
+	if len(stats.PagesPerDay) != 60 || stats.PagesRead != 50 {
+		t.Errorf("Expected 60 days and 50 pages, got %d / %d", len(stats.PagesPerDay), stats.PagesRead)
+	}
+	// 2024-01-01 is a Monday, so each reading day starts its own week
+	if stats.PagesPerWeek[0].WeekStart != "2024-01-01" || stats.PagesPerWeek[0].Pages != 30 || stats.PagesPerWeek[1].Pages != 20 {
+		t.Errorf("Unexpected weekly pages %+v", stats.PagesPerWeek[:2])
+	}
+	if stats.AverageDaysToFinish == nil || *stats.AverageDaysToFinish != 4 {
+		t.Errorf("Expected 4 days to finish, got %v", stats.AverageDaysToFinish)
+	}
+	if stats.Genres[0].Name != "Sci-Fi" || stats.Genres[0].Books != 2 || stats.Authors[0].Name != "Le Guin" {
+		t.Errorf("Unexpected breakdowns %+v / %+v", stats.Genres, stats.Authors)
+	}
+}
+
+func TestGetStats_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	service := NewStatsService(&FakeStatsRepo{})
+
+	for _, q := range []dto.StatsQuery{
+		{From: "2024-03-01", To: "2024-02-01"},
+		{From: "yesterday"},
+		{From: "2010-01-01", To: "2024-01-01"},
+	} {
+		if _, err := service.GetStats(ctx, 1, q); !errors.Is(err, ErrInvalidStatsRange) {
+			t.Errorf("Expected ErrInvalidStatsRange for %+v, got %v", q, err)
+		}
+	}
+}
+
+func TestReadingStreaks(t *testing.T) {
+	days := []time.Time{
+		day("2024-03-01"), day("2024-03-02"), day("2024-03-03"),
+		day("2024-03-10"), day("2024-03-11"),
+	}
+
+	streaks := readingStreaks(days, day("2024-03-12"))
+	if streaks.Longest != 3 || streaks.Current != 2 || streaks.LastReadOn != "2024-03-11" {
+		t.Errorf("Unexpected streaks %+v", streaks)
+	}
+
+	broken := readingStreaks(days, day("2024-03-13"))
+	if broken.Current != 0 || broken.Longest != 3 {
+		t.Errorf("Expected the current streak to be broken, got %+v", broken)
+	}
+}
+
+func TestGetStats_CountsDidNotFinish(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeStatsRepo{
+		Reads:     []dto.FinishedRead{{FinishedAt: day("2024-01-05")}},
+		Abandoned: 2,
+	}
+	service := NewStatsService(repo)
+
+	stats, err := service.GetStats(ctx, 1, dto.StatsQuery{From: "2024-01-01", To: "2024-02-29"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if stats.BooksFinished != 1 || stats.DidNotFinish != 2 {
+		t.Errorf("Expected 1 finished and 2 abandoned, got %d and %d", stats.BooksFinished, stats.DidNotFinish)
+	}
+}
@@ -1,30 +1,55 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+	"github.com/Aiswaryar123/ReadingTrackerProject/configs"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrFollowSelf         = errors.New("you cannot follow yourself")
+	ErrNoFollowRequest    = errors.New("this user has not asked to follow you")
+	ErrInvalidCredentials = errors.New("invalid email or password")
 )
 
 type UserService interface {
-	Register(req dto.RegisterRequest) (*models.User, error)
-	Login(req dto.LoginRequest) (string, error)
+	Register(ctx context.Context, req dto.RegisterRequest) (*models.User, error)
+	Login(ctx context.Context, req dto.LoginRequest) (*dto.TokenResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenResponse, error)
+	Logout(ctx context.Context, sessionID string) error
+	GetSettings(ctx context.Context, userID uint) (*dto.UserSettings, error)
+	UpdateSettings(ctx context.Context, userID uint, req dto.UserSettings) (*dto.UserSettings, error)
+	Follow(ctx context.Context, userID uint, targetID uint) error
+	Unfollow(ctx context.Context, userID uint, targetID uint) error
+	GetFollowRequests(ctx context.Context, userID uint) ([]dto.FollowRequest, error)
+	ApproveFollower(ctx context.Context, userID uint, followerID uint) error
+	RemoveFollower(ctx context.Context, userID uint, followerID uint) error
 }
 type userService struct {
-	repo repository.UserRepository
+	repo      repository.UserRepository
+	tokenRepo repository.TokenRepository
+	jwt       configs.JWTConfig
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, tokenRepo repository.TokenRepository, jwt configs.JWTConfig) UserService {
+	return &userService{repo: repo, tokenRepo: tokenRepo, jwt: jwt}
 }
 
-func (s *userService) Register(req dto.RegisterRequest) (*models.User, error) {
+func (s *userService) Register(ctx context.Context, req dto.RegisterRequest) (*models.User, error) {
 
-	existingUser, _ := s.repo.FindByEmail(req.Email)
+	existingUser, _ := s.repo.FindByEmail(ctx, req.Email)
 	if existingUser != nil {
 		return nil, errors.New("email already registered")
 	}
@@ -35,12 +60,13 @@ func (s *userService) Register(req dto.RegisterRequest) (*models.User, error) {
 	}
 
 	user := &models.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
+		Name:        req.Name,
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		Email:       req.Email,
+		Password:    string(hashedPassword),
 	}
 
-	err = s.repo.CreateUser(user)
+	err = s.repo.CreateUser(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -48,21 +74,170 @@ func (s *userService) Register(req dto.RegisterRequest) (*models.User, error) {
 	return user, nil
 }
 
-func (s *userService) Login(req dto.LoginRequest) (string, error) {
-	user, err := s.repo.FindByEmail(req.Email)
+func (s *userService) Login(ctx context.Context, req dto.LoginRequest) (*dto.TokenResponse, error) {
+	// only a wrong email or password counts as a failed login; a database
+	// outage would otherwise look like a credential stuffing attack
+	user, err := s.repo.FindByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		metrics.LoginsFailed.Inc()
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		metrics.LoginsFailed.Inc()
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return s.issueTokens(ctx, user.ID, sessionID)
+}
+
+func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenResponse, error) {
+	stored, err := s.tokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// a rotated token coming back means it was copied, so kill the whole session
+	if stored.RevokedAt != nil {
+		logging.FromContext(ctx).Warn("refresh token reuse detected, revoking session", "user_id", stored.UserID)
+		s.tokenRepo.RevokeSession(ctx, stored.SessionID)
+		return nil, errors.New("refresh token reuse detected, please log in again")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token expired, please log in again")
+	}
+
+	tokens, next, err := s.newTokens(stored.UserID, stored.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tokenRepo.RotateToken(ctx, stored.ID, next)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.tokenRepo.RevokeSession(ctx, stored.SessionID)
+		return nil, errors.New("refresh token reuse detected, please log in again")
+	}
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	return tokens, nil
+}
+
+func (s *userService) Logout(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return errors.New("no active session")
+	}
+	return s.tokenRepo.RevokeSession(ctx, sessionID)
+}
+
+func (s *userService) GetSettings(ctx context.Context, userID uint) (*dto.UserSettings, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return &dto.UserSettings{DisplayName: &user.DisplayName, DefaultReviewVisibility: user.DefaultReviewVisibility}, nil
+}
+
+// UpdateSettings only changes the fields that were sent.
+func (s *userService) UpdateSettings(ctx context.Context, userID uint, req dto.UserSettings) (*dto.UserSettings, error) {
+	user, err := s.repo.FindByID(ctx, userID)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, ErrUserNotFound
+	}
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.DefaultReviewVisibility != "" {
+		user.DefaultReviewVisibility = req.DefaultReviewVisibility
 	}
+	if err := s.repo.UpdateSettings(ctx, user); err != nil {
+		return nil, err
+	}
+	return &dto.UserSettings{DisplayName: &user.DisplayName, DefaultReviewVisibility: user.DefaultReviewVisibility}, nil
+}
+
+// Follow asks to follow targetID; followers-only reviews stay hidden until
+// targetID approves the request.
+func (s *userService) Follow(ctx context.Context, userID uint, targetID uint) error {
+	if userID == targetID {
+		return ErrFollowSelf
+	}
+	if _, err := s.repo.FindByID(ctx, targetID); err != nil {
+		return ErrUserNotFound
+	}
+	return s.repo.Follow(ctx, &models.Follow{FollowerID: userID, FolloweeID: targetID})
+}
+
+func (s *userService) Unfollow(ctx context.Context, userID uint, targetID uint) error {
+	return s.repo.Unfollow(ctx, userID, targetID)
+}
+
+func (s *userService) GetFollowRequests(ctx context.Context, userID uint) ([]dto.FollowRequest, error) {
+	requests, err := s.repo.GetFollowRequests(ctx, userID)
+	if requests == nil {
+		requests = []dto.FollowRequest{}
+	}
+	return requests, err
+}
 
-	token, err := utils.GenerateToken(user.ID)
+func (s *userService) ApproveFollower(ctx context.Context, userID uint, followerID uint) error {
+	err := s.repo.ApproveFollow(ctx, followerID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNoFollowRequest
+	}
+	return err
+}
+
+// RemoveFollower turns down a pending request or drops an approved follower.
+func (s *userService) RemoveFollower(ctx context.Context, userID uint, followerID uint) error {
+	return s.repo.Unfollow(ctx, followerID, userID)
+}
+
+func (s *userService) issueTokens(ctx context.Context, userID uint, sessionID string) (*dto.TokenResponse, error) {
+	tokens, stored, err := s.newTokens(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.CreateRefreshToken(ctx, stored); err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	return tokens, nil
+}
+
+// newTokens builds a token pair and the refresh token row to store for it.
+func (s *userService) newTokens(userID uint, sessionID string) (*dto.TokenResponse, *models.RefreshToken, error) {
+	accessToken, err := utils.GenerateToken(userID, sessionID, s.jwt.Secret, s.jwt.AccessTokenTTL.Duration)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	refreshToken, err := utils.GenerateRandomToken()
 	if err != nil {
-		return "", errors.New("failed to generate token")
+		return nil, nil, errors.New("failed to generate token")
 	}
 
-	return token, nil
+	stored := &models.RefreshToken{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.jwt.RefreshTokenTTL.Duration),
+	}
+	tokens := &dto.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.jwt.AccessTokenTTL.Seconds()),
+	}
+	return tokens, stored, nil
 }
@@ -1,39 +1,172 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"os"
 	"testing"
+	"time"
 
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/metrics"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/utils"
+	"github.com/Aiswaryar123/ReadingTrackerProject/configs"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+var testJWT = configs.JWTConfig{
+	Secret:          "test_secret",
+	AccessTokenTTL:  configs.Duration{Duration: 15 * time.Minute},
+	RefreshTokenTTL: configs.Duration{Duration: 30 * 24 * time.Hour},
+}
+
 type FakeUserRepo struct {
-	Users []models.User
+	Users   []models.User
+	Follows []models.Follow
+	Err     error
 }
 
-func (f *FakeUserRepo) CreateUser(user *models.User) error {
+func (f *FakeUserRepo) CreateUser(ctx context.Context, user *models.User) error {
 	f.Users = append(f.Users, *user)
 	return nil
 }
 
-func (f *FakeUserRepo) FindByEmail(email string) (*models.User, error) {
+func (f *FakeUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
 	for _, u := range f.Users {
 		if u.Email == email {
 			return &u, nil
 		}
 	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *FakeUserRepo) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	for _, u := range f.Users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
 	return nil, errors.New("not found")
 }
 
-func (f *FakeUserRepo) FindByID(id uint) (*models.User, error) { return nil, nil }
+func (f *FakeUserRepo) UpdateSettings(ctx context.Context, user *models.User) error {
+	for i := range f.Users {
+		if f.Users[i].ID == user.ID {
+			f.Users[i] = *user
+		}
+	}
+	return nil
+}
+
+func (f *FakeUserRepo) Follow(ctx context.Context, follow *models.Follow) error {
+	f.Follows = append(f.Follows, *follow)
+	return nil
+}
+
+func (f *FakeUserRepo) Unfollow(ctx context.Context, followerID uint, followeeID uint) error {
+	kept := f.Follows[:0]
+	for _, fl := range f.Follows {
+		if fl.FollowerID != followerID || fl.FolloweeID != followeeID {
+			kept = append(kept, fl)
+		}
+	}
+	f.Follows = kept
+	return nil
+}
+
+func (f *FakeUserRepo) IsFollowing(ctx context.Context, followerID uint, followeeID uint) (bool, error) {
+	for _, fl := range f.Follows {
+		if fl.FollowerID == followerID && fl.FolloweeID == followeeID && fl.Approved {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *FakeUserRepo) ApproveFollow(ctx context.Context, followerID uint, followeeID uint) error {
+	for i, fl := range f.Follows {
+		if fl.FollowerID == followerID && fl.FolloweeID == followeeID {
+			f.Follows[i].Approved = true
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (f *FakeUserRepo) GetFollowRequests(ctx context.Context, followeeID uint) ([]dto.FollowRequest, error) {
+	var requests []dto.FollowRequest
+	for _, fl := range f.Follows {
+		if fl.FolloweeID == followeeID && !fl.Approved {
+			requests = append(requests, dto.FollowRequest{FollowerID: fl.FollowerID, RequestedAt: fl.CreatedAt})
+		}
+	}
+	return requests, nil
+}
+
+type FakeTokenRepo struct {
+	Tokens    []models.RefreshToken
+	CreateErr error
+}
+
+func (f *FakeTokenRepo) CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error {
+	t.ID = uint(len(f.Tokens) + 1)
+	f.Tokens = append(f.Tokens, *t)
+	return nil
+}
+
+func (f *FakeTokenRepo) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	for _, t := range f.Tokens {
+		if t.TokenHash == hash {
+			return &t, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *FakeTokenRepo) RotateToken(ctx context.Context, oldID uint, next *models.RefreshToken) error {
+	for i := range f.Tokens {
+		if f.Tokens[i].ID == oldID && f.Tokens[i].RevokedAt == nil {
+			// like the transaction, a failed insert leaves the old token alone
+			if f.CreateErr != nil {
+				return f.CreateErr
+			}
+			now := time.Now()
+			f.Tokens[i].RevokedAt = &now
+			return f.CreateRefreshToken(ctx, next)
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (f *FakeTokenRepo) RevokeSession(ctx context.Context, sessionID string) error {
+	now := time.Now()
+	for i := range f.Tokens {
+		if f.Tokens[i].SessionID == sessionID && f.Tokens[i].RevokedAt == nil {
+			f.Tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *FakeTokenRepo) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	for _, t := range f.Tokens {
+		if t.SessionID == sessionID && t.RevokedAt == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
 func TestRegister_Success(t *testing.T) {
+	ctx := context.Background()
 	repo := &FakeUserRepo{}
-	service := NewUserService(repo)
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
 
 	req := dto.RegisterRequest{
 		Name:     "Test User",
@@ -41,7 +174,7 @@ func TestRegister_Success(t *testing.T) {
 		Password: "password123",
 	}
 
-	user, err := service.Register(req)
+	user, err := service.Register(ctx, req)
 
 	if err != nil {
 		t.Errorf("Expected successful registration, but got error: %v", err)
@@ -56,10 +189,10 @@ func TestRegister_Success(t *testing.T) {
 }
 
 func TestRegister_DuplicateEmail(t *testing.T) {
-
+	ctx := context.Background()
 	existingUser := models.User{Email: "existing@example.com"}
 	repo := &FakeUserRepo{Users: []models.User{existingUser}}
-	service := NewUserService(repo)
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
 
 	req := dto.RegisterRequest{
 		Name:     "New User",
@@ -67,7 +200,7 @@ func TestRegister_DuplicateEmail(t *testing.T) {
 		Password: "password123",
 	}
 
-	_, err := service.Register(req)
+	_, err := service.Register(ctx, req)
 
 	if err == nil {
 		t.Errorf("Expected error for duplicate email, but got nil")
@@ -75,8 +208,7 @@ func TestRegister_DuplicateEmail(t *testing.T) {
 }
 
 func TestLogin_Success(t *testing.T) {
-	os.Setenv("JWT_SECRET", "test_secret")
-
+	ctx := context.Background()
 	pass := "secret123"
 	hashed, _ := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 	repo := &FakeUserRepo{
@@ -84,39 +216,260 @@ func TestLogin_Success(t *testing.T) {
 			{ID: 1, Email: "login@example.com", Password: string(hashed)},
 		},
 	}
-	service := NewUserService(repo)
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
 
 	req := dto.LoginRequest{
 		Email:    "login@example.com",
 		Password: "secret123",
 	}
 
-	token, err := service.Login(req)
+	tokens, err := service.Login(ctx, req)
 
 	if err != nil {
-		t.Errorf("Expected successful login, but got error: %v", err)
+		t.Fatalf("Expected successful login, but got error: %v", err)
 	}
 
-	if token == "" {
+	if tokens.Token == "" {
 		t.Errorf("Expected a JWT token string, but got an empty string")
 	}
+	if tokens.RefreshToken == "" {
+		t.Errorf("Expected a refresh token, but got an empty string")
+	}
 }
 func TestLogin_WrongPassword(t *testing.T) {
-
+	ctx := context.Background()
 	hashed, _ := bcrypt.GenerateFromPassword([]byte("realpass"), bcrypt.DefaultCost)
 	repo := &FakeUserRepo{
 		Users: []models.User{{Email: "user@example.com", Password: string(hashed)}},
 	}
-	service := NewUserService(repo)
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
 
 	req := dto.LoginRequest{
 		Email:    "user@example.com",
 		Password: "wrongpassword",
 	}
 
-	_, err := service.Login(req)
+	_, err := service.Login(ctx, req)
 
 	if err == nil {
 		t.Errorf("Expected error for wrong password, but got nil")
 	}
 }
+
+func TestLogin_FailureIsCounted(t *testing.T) {
+	ctx := context.Background()
+	service := NewUserService(&FakeUserRepo{}, &FakeTokenRepo{}, testJWT)
+	before := testutil.ToFloat64(metrics.LoginsFailed)
+
+	_, err := service.Login(ctx, dto.LoginRequest{Email: "nobody@example.com", Password: "whatever"})
+
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.LoginsFailed) - before; got != 1 {
+		t.Errorf("Expected one failed login to be counted, got %v", got)
+	}
+}
+
+func TestLogin_WrongPasswordIsCounted(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("right-password"), bcrypt.MinCost)
+	repo := &FakeUserRepo{Users: []models.User{{ID: 1, Email: "reader@example.com", Password: string(hash)}}}
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
+	before := testutil.ToFloat64(metrics.LoginsFailed)
+
+	_, err := service.Login(ctx, dto.LoginRequest{Email: "reader@example.com", Password: "wrong-password"})
+
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.LoginsFailed) - before; got != 1 {
+		t.Errorf("Expected one failed login to be counted, got %v", got)
+	}
+}
+
+func TestLogin_DatabaseErrorIsNotCounted(t *testing.T) {
+	ctx := context.Background()
+	dbErr := errors.New("connection refused")
+	service := NewUserService(&FakeUserRepo{Err: dbErr}, &FakeTokenRepo{}, testJWT)
+	before := testutil.ToFloat64(metrics.LoginsFailed)
+
+	_, err := service.Login(ctx, dto.LoginRequest{Email: "reader@example.com", Password: "whatever"})
+
+	if !errors.Is(err, dbErr) {
+		t.Errorf("Expected the database error, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.LoginsFailed) - before; got != 0 {
+		t.Errorf("Expected a database error not to count as a failed login, got %v", got)
+	}
+}
+
+func TestRefreshToken_Rotation(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := &FakeTokenRepo{}
+	tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    1,
+		SessionID: "session-1",
+		TokenHash: utils.HashToken("old-refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	service := NewUserService(&FakeUserRepo{}, tokenRepo, testJWT)
+
+	tokens, err := service.RefreshToken(ctx, "old-refresh")
+	if err != nil {
+		t.Fatalf("Expected refresh to succeed, but got error: %v", err)
+	}
+	if tokens.RefreshToken == "old-refresh" {
+		t.Errorf("Expected a rotated refresh token")
+	}
+	if tokenRepo.Tokens[0].RevokedAt == nil {
+		t.Errorf("Expected the old refresh token to be revoked")
+	}
+}
+
+func TestRefreshToken_FailedRotationKeepsOldToken(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := &FakeTokenRepo{}
+	tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    1,
+		SessionID: "session-1",
+		TokenHash: utils.HashToken("old-refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	service := NewUserService(&FakeUserRepo{}, tokenRepo, testJWT)
+
+	tokenRepo.CreateErr = errors.New("connection refused")
+	if _, err := service.RefreshToken(ctx, "old-refresh"); err == nil {
+		t.Fatalf("Expected the refresh to fail")
+	}
+	if active, _ := tokenRepo.IsSessionActive(ctx, "session-1"); !active {
+		t.Errorf("Expected the session to survive a failed rotation")
+	}
+
+	tokenRepo.CreateErr = nil
+	if _, err := service.RefreshToken(ctx, "old-refresh"); err != nil {
+		t.Errorf("Expected the old token to still work, got %v", err)
+	}
+}
+
+func TestRefreshToken_ReuseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := &FakeTokenRepo{}
+	tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    1,
+		SessionID: "session-1",
+		TokenHash: utils.HashToken("old-refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	service := NewUserService(&FakeUserRepo{}, tokenRepo, testJWT)
+
+	if _, err := service.RefreshToken(ctx, "old-refresh"); err != nil {
+		t.Fatalf("Expected first refresh to succeed, but got error: %v", err)
+	}
+
+	_, err := service.RefreshToken(ctx, "old-refresh")
+	if err == nil {
+		t.Errorf("Expected error for a reused refresh token, but got nil")
+	}
+
+	active, _ := tokenRepo.IsSessionActive(ctx, "session-1")
+	if active {
+		t.Errorf("Expected the whole session to be revoked after reuse")
+	}
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := &FakeTokenRepo{}
+	tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    1,
+		SessionID: "session-1",
+		TokenHash: utils.HashToken("refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	service := NewUserService(&FakeUserRepo{}, tokenRepo, testJWT)
+
+	if err := service.Logout(ctx, "session-1"); err != nil {
+		t.Fatalf("Expected logout to succeed, but got error: %v", err)
+	}
+
+	if _, err := service.RefreshToken(ctx, "refresh"); err == nil {
+		t.Errorf("Expected refresh to fail after logout, but got nil")
+	}
+}
+
+func TestFollow_Self(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeUserRepo{Users: []models.User{{ID: 1}}}
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
+
+	if err := service.Follow(ctx, 1, 1); !errors.Is(err, ErrFollowSelf) {
+		t.Errorf("Expected ErrFollowSelf, got %v", err)
+	}
+	if err := service.Follow(ctx, 1, 9); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestFollow_NeedsApproval(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeUserRepo{Users: []models.User{{ID: 1}, {ID: 2}}}
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
+
+	if err := service.Follow(ctx, 1, 2); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if following, _ := repo.IsFollowing(ctx, 1, 2); following {
+		t.Errorf("Expected the follow to wait for approval")
+	}
+	requests, _ := service.GetFollowRequests(ctx, 2)
+	if len(requests) != 1 || requests[0].FollowerID != 1 {
+		t.Fatalf("Expected one pending request from user 1, got %+v", requests)
+	}
+
+	if err := service.ApproveFollower(ctx, 1, 2); !errors.Is(err, ErrNoFollowRequest) {
+		t.Errorf("Expected the follower not to approve themselves, got %v", err)
+	}
+	if err := service.ApproveFollower(ctx, 2, 1); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if following, _ := repo.IsFollowing(ctx, 1, 2); !following {
+		t.Errorf("Expected an approved follow to count")
+	}
+
+	if err := service.RemoveFollower(ctx, 2, 1); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if following, _ := repo.IsFollowing(ctx, 1, 2); following {
+		t.Errorf("Expected a removed follower to lose access")
+	}
+}
+
+func TestUpdateSettings_KeepsVisibilityWhenOmitted(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeUserRepo{Users: []models.User{{ID: 1, DefaultReviewVisibility: models.VisibilityPrivate}}}
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
+
+	name := " Ana "
+	settings, err := service.UpdateSettings(ctx, 1, dto.UserSettings{DisplayName: &name})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if *settings.DisplayName != "Ana" || settings.DefaultReviewVisibility != models.VisibilityPrivate {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+}
+
+func TestUpdateSettings_KeepsDisplayNameWhenOmitted(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeUserRepo{Users: []models.User{{ID: 1, DisplayName: "Ana", DefaultReviewVisibility: models.VisibilityPrivate}}}
+	service := NewUserService(repo, &FakeTokenRepo{}, testJWT)
+
+	settings, err := service.UpdateSettings(ctx, 1, dto.UserSettings{DefaultReviewVisibility: models.VisibilityPublic})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if *settings.DisplayName != "Ana" || settings.DefaultReviewVisibility != models.VisibilityPublic {
+		t.Errorf("Expected the display name to be kept, got %+v", settings)
+	}
+}
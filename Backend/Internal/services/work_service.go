@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrWorkNotFound = errors.New("work not found")
+
+type WorkService interface {
+	GetWork(ctx context.Context, workID uint) (*dto.WorkDetail, error)
+}
+
+type workService struct {
+	repo repository.WorkRepository
+}
+
+func NewWorkService(repo repository.WorkRepository) WorkService {
+	return &workService{repo: repo}
+}
+
+func (s *workService) GetWork(ctx context.Context, workID uint) (*dto.WorkDetail, error) {
+	work, err := s.repo.GetWork(ctx, workID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWorkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.repo.GetWorkStats(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WorkDetail{Work: *work, Stats: stats}, nil
+}
+
+// WorkKey identifies a work by title and author, ignoring case and spacing.
+// The boot-time backfill in the database package builds the same key in SQL.
+func WorkKey(title string, author string) string {
+	normalize := func(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), " ")) }
+	return normalize(title) + "|" + normalize(author)
+}
+
+// linkToCatalog points a library entry at its shared work and edition,
+// creating them on first sight. A known ISBN wins over title/author, so
+// differently spelled entries of the same edition still meet.
+func linkToCatalog(ctx context.Context, repo repository.WorkRepository, book *models.Book) error {
+	if book.ISBN != "" {
+		if edition, err := repo.FindEditionByISBN(ctx, book.ISBN); err == nil {
+			book.WorkID = &edition.WorkID
+			book.EditionID = &edition.ID
+			return nil
+		}
+	}
+
+	work := &models.Work{
+		Key:    WorkKey(book.Title, book.Author),
+		Title:  strings.TrimSpace(book.Title),
+		Author: strings.TrimSpace(book.Author),
+		Genre:  book.Genre,
+	}
+	if err := repo.FindOrCreateWork(ctx, work); err != nil {
+		return err
+	}
+	book.WorkID = &work.ID
+
+	if book.ISBN == "" {
+		book.EditionID = nil
+		return nil
+	}
+	edition := &models.Edition{
+		WorkID:          work.ID,
+		ISBN:            book.ISBN,
+		PublicationYear: book.PublicationYear,
+		TotalPages:      book.TotalPages,
+	}
+	if err := repo.FindOrCreateEdition(ctx, edition); err != nil {
+		return err
+	}
+	book.EditionID = &edition.ID
+	return nil
+}
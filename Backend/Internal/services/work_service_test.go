@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/dto"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type FakeWorkRepo struct {
+	Works    []models.Work
+	Editions []models.Edition
+	Stats    dto.WorkStats
+	Err      error
+}
+
+func (f *FakeWorkRepo) FindOrCreateWork(ctx context.Context, work *models.Work) error {
+	for _, w := range f.Works {
+		if w.Key == work.Key {
+			*work = w
+			return nil
+		}
+	}
+	work.ID = uint(len(f.Works) + 1)
+	f.Works = append(f.Works, *work)
+	return nil
+}
+
+func (f *FakeWorkRepo) FindEditionByISBN(ctx context.Context, isbn string) (*models.Edition, error) {
+	for i := range f.Editions {
+		if f.Editions[i].ISBN == isbn {
+			return &f.Editions[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *FakeWorkRepo) FindOrCreateEdition(ctx context.Context, edition *models.Edition) error {
+	if existing, err := f.FindEditionByISBN(ctx, edition.ISBN); err == nil {
+		*edition = *existing
+		return nil
+	}
+	edition.ID = uint(len(f.Editions) + 1)
+	f.Editions = append(f.Editions, *edition)
+	return nil
+}
+
+func (f *FakeWorkRepo) GetWork(ctx context.Context, id uint) (*models.Work, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for i := range f.Works {
+		if f.Works[i].ID == id {
+			return &f.Works[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *FakeWorkRepo) GetWorkStats(ctx context.Context, workID uint) (dto.WorkStats, error) {
+	return f.Stats, nil
+}
+
+func TestCreateBook_SameWorkAcrossUsers(t *testing.T) {
+	ctx := context.Background()
+	works := &FakeWorkRepo{}
+	service := NewBookService(&FakeBookRepo{}, works, nil)
+
+	first, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	second, err := service.CreateBook(ctx, 2, dto.CreateBookRequest{Title: "  the hobbit ", Author: "J.R.R.  Tolkien"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if first.WorkID == nil || second.WorkID == nil || *first.WorkID != *second.WorkID {
+		t.Errorf("Expected both entries to share a work, got %v and %v", first.WorkID, second.WorkID)
+	}
+	if len(works.Works) != 1 {
+		t.Errorf("Expected 1 work in the catalog, got %d", len(works.Works))
+	}
+}
+
+func TestCreateBook_SameTitleAndAuthorForTwoUsers(t *testing.T) {
+	ctx := context.Background()
+	books := &FakeBookRepo{}
+	service := NewBookService(books, &FakeWorkRepo{}, nil)
+
+	req := dto.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien"}
+	if _, err := service.CreateBook(ctx, 1, req); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if _, err := service.CreateBook(ctx, 2, req); err != nil {
+		t.Fatalf("Expected a second user to add the same book, got %v", err)
+	}
+	if _, err := service.CreateBook(ctx, 1, req); err == nil {
+		t.Errorf("Expected the same user adding it twice to fail")
+	}
+	if len(books.Books) != 2 {
+		t.Errorf("Expected 2 library entries, got %d", len(books.Books))
+	}
+}
+
+func TestBookUniqueIndex_IsPerUser(t *testing.T) {
+	s, err := schema.Parse(&models.Book{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	idx := s.LookIndex("idx_user_title_author")
+	if idx == nil || idx.Class != "UNIQUE" {
+		t.Fatalf("Expected unique index idx_user_title_author, got %+v", idx)
+	}
+	var columns []string
+	for _, f := range idx.Fields {
+		columns = append(columns, f.DBName)
+	}
+	if strings.Join(columns, ",") != "user_id,title,author" {
+		t.Errorf("Expected index on user_id,title,author, got %v", columns)
+	}
+}
+
+func TestCreateBook_ISBNDecidesWork(t *testing.T) {
+	ctx := context.Background()
+	works := &FakeWorkRepo{
+		Works:    []models.Work{{ID: 1, Key: WorkKey("The Hobbit", "J.R.R. Tolkien"), Title: "The Hobbit", Author: "J.R.R. Tolkien"}},
+		Editions: []models.Edition{{ID: 7, WorkID: 1, ISBN: "9780618260300"}},
+	}
+	service := NewBookService(&FakeBookRepo{}, works, nil)
+
+	book, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "Hobbit, The", Author: "Tolkien", ISBN: "9780618260300"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if book.WorkID == nil || *book.WorkID != 1 || book.EditionID == nil || *book.EditionID != 7 {
+		t.Errorf("Expected work 1 / edition 7, got %v / %v", book.WorkID, book.EditionID)
+	}
+}
+
+func TestCreateBook_NewEditionOfKnownWork(t *testing.T) {
+	ctx := context.Background()
+	works := &FakeWorkRepo{
+		Works:    []models.Work{{ID: 1, Key: WorkKey("The Hobbit", "J.R.R. Tolkien")}},
+		Editions: []models.Edition{{ID: 7, WorkID: 1, ISBN: "9780618260300"}},
+	}
+	service := NewBookService(&FakeBookRepo{}, works, nil)
+
+	book, err := service.CreateBook(ctx, 1, dto.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", ISBN: "9780134685991"})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if *book.WorkID != 1 || len(works.Editions) != 2 {
+		t.Errorf("Expected a second edition of work 1, got work %v and %d editions", *book.WorkID, len(works.Editions))
+	}
+}
+
+func TestGetWork_NotFound(t *testing.T) {
+	ctx := context.Background()
+	service := NewWorkService(&FakeWorkRepo{})
+
+	_, err := service.GetWork(ctx, 42)
+	if !errors.Is(err, ErrWorkNotFound) {
+		t.Errorf("Expected ErrWorkNotFound, got %v", err)
+	}
+}
+
+func TestGetWork_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	dbErr := errors.New("connection refused")
+	service := NewWorkService(&FakeWorkRepo{Err: dbErr})
+
+	_, err := service.GetWork(ctx, 1)
+	if !errors.Is(err, dbErr) || errors.Is(err, ErrWorkNotFound) {
+		t.Errorf("Expected the repository error, got %v", err)
+	}
+}
+
+func TestGetWork_IncludesStats(t *testing.T) {
+	ctx := context.Background()
+	repo := &FakeWorkRepo{
+		Works: []models.Work{{ID: 1, Title: "The Hobbit"}},
+		Stats: dto.WorkStats{Readers: 3, ReviewCount: 2, AverageRating: 4.5},
+	}
+	service := NewWorkService(repo)
+
+	work, err := service.GetWork(ctx, 1)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if work.Stats.Readers != 3 || work.Stats.AverageRating != 4.5 {
+		t.Errorf("Got %+v", work.Stats)
+	}
+}
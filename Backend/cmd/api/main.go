@@ -1,44 +1,158 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/catalog"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/database"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/handlers"
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/logging"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/middleware"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/repository"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/routes"
 	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/services"
 	"github.com/Aiswaryar123/ReadingTrackerProject/configs"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
 
-	cfg := configs.LoadConfig()
+	cfg, args, err := configs.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// the standard log package writes through this too
+	logger := logging.New(os.Stdout, cfg.Log.Level)
+	slog.SetDefault(logger)
 
 	database.ConnectDB(cfg)
 
+	if len(args) > 0 {
+		if err := runCommand(args); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := prepareSchema(cfg.Database.AutoMigrate); err != nil {
+		log.Fatal(err)
+	}
+
 	userRepo := repository.NewUserRepository(database.DB)
 	bookRepo := repository.NewBookRepository(database.DB)
 	progressRepo := repository.NewProgressRepository(database.DB)
 	reviewRepo := repository.NewReviewRepository(database.DB)
 	goalRepo := repository.NewGoalRepository(database.DB)
+	tokenRepo := repository.NewTokenRepository(database.DB)
+	shelfRepo := repository.NewShelfRepository(database.DB)
+	workRepo := repository.NewWorkRepository(database.DB)
+	statsRepo := repository.NewStatsRepository(database.DB)
 
-	userService := services.NewUserService(userRepo)
-	bookService := services.NewBookService(bookRepo)
+	userService := services.NewUserService(userRepo, tokenRepo, cfg.JWT)
+	catalogProvider, err := catalog.NewProvider(cfg.Catalog.Provider, cfg.Catalog.URL, cfg.Catalog.Fixture)
+	if err != nil {
+		log.Fatal("Failed to set up book catalog: ", err)
+	}
+
+	bookService := services.NewBookService(bookRepo, workRepo, catalogProvider)
 	progressService := services.NewProgressService(progressRepo, bookRepo)
-	reviewService := services.NewReviewService(reviewRepo, bookRepo)
+	reviewService := services.NewReviewService(reviewRepo, bookRepo, progressRepo, userRepo)
 	goalService := services.NewGoalService(goalRepo)
+	importService := services.NewImportService(bookRepo, progressRepo, reviewRepo, workRepo, userRepo)
+	exportService := services.NewExportService(bookRepo, reviewRepo, goalRepo)
+	shelfService := services.NewShelfService(shelfRepo)
+	workService := services.NewWorkService(workRepo)
+	statsService := services.NewStatsService(statsRepo)
+	reportService := services.NewReportService(statsRepo, goalRepo)
 
 	userHandler := handlers.NewUserHandler(userService)
 	bookHandler := handlers.NewBookHandler(bookService)
 	progressHandler := handlers.NewProgressHandler(progressService)
 	reviewHandler := handlers.NewReviewHandler(reviewService)
 	goalHandler := handlers.NewGoalHandler(goalService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	shelfHandler := handlers.NewShelfHandler(shelfService)
+	workHandler := handlers.NewWorkHandler(workService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	reportHandler := handlers.NewReportHandler(reportService)
+
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	healthHandler := handlers.NewHealthHandler(sqlDB)
+
+	r := gin.New()
+	r.Use(middleware.RequestLogger(logger, "/healthz", "/readyz"), middleware.Metrics(), middleware.Recovery())
+	r.Use(middleware.CORSMiddleware(cfg.CORS.AllowedOrigins))
+
+	routes.RegisterRoutes(r, userHandler, bookHandler, progressHandler, reviewHandler, goalHandler, importHandler, exportHandler, shelfHandler, workHandler, statsHandler, reportHandler, healthHandler, tokenRepo, cfg.JWT.Secret)
+
+	srv := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      r,
+		ReadTimeout:  cfg.Server.ReadTimeout.Duration,
+		WriteTimeout: cfg.Server.WriteTimeout.Duration,
+		IdleTimeout:  cfg.Server.IdleTimeout.Duration,
+	}
+
+	// metrics are unauthenticated, so they get their own listener that is
+	// never routed through the public proxy
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	metricsSrv := &http.Server{
+		Addr:              cfg.Server.MetricsAddr,
+		Handler:           metricsMux,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout.Duration,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	go func() {
+		serveErr <- metricsSrv.ListenAndServe()
+	}()
+	log.Printf("Listening on :%s, metrics on %s", cfg.Server.Port, cfg.Server.MetricsAddr)
 
-	r := gin.Default()
-	r.Use(middleware.CORSMiddleware())
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		stop()
+		healthHandler.Drain()
+		log.Printf("Shutting down, failing readiness for %s before closing the listener", cfg.Server.DrainDelay)
+		time.Sleep(cfg.Server.DrainDelay.Duration)
 
-	routes.RegisterRoutes(r, userHandler, bookHandler, progressHandler, reviewHandler, goalHandler)
+		log.Printf("Waiting up to %s for in-flight requests", cfg.Server.ShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Duration)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown did not finish cleanly: %v", err)
+		}
+		// keep metrics up until the API is done so the last requests are scraped
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Metrics server did not shut down cleanly: %v", err)
+		}
+	}
 
-	r.Run(":" + cfg.Port)
+	if err := database.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	log.Println("Server stopped")
 }
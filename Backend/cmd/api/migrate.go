@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Aiswaryar123/ReadingTrackerProject/Internal/database"
+)
+
+const usage = `usage: api [flags] [migrate up | migrate down [steps] | migrate status]`
+
+func runCommand(args []string) error {
+	if args[0] != "migrate" || len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	switch args[1] {
+	case "up":
+		applied, err := database.MigrateUp(database.DB)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migrations applied\n", applied)
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[2])
+			}
+			steps = n
+		}
+		return database.MigrateDown(database.DB, steps)
+	case "status":
+		states, err := database.MigrationStatus(database.DB)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range states {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	}
+	return errors.New(usage)
+}
+
+// prepareSchema runs pending migrations on startup, or with auto-migrate
+// turned off, refuses to serve an out-of-date schema.
+func prepareSchema(autoMigrate bool) error {
+	if autoMigrate {
+		if _, err := database.MigrateUp(database.DB); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		return nil
+	}
+
+	states, err := database.MigrationStatus(database.DB)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range states {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d database migrations are pending, run `api migrate up` first", pending)
+	}
+	return nil
+}
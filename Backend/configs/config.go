@@ -1,36 +1,331 @@
 package configs
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+const minJWTSecretLength = 16
+
+// Config is loaded in layers: built-in defaults, then an optional JSON file
+// (-config or CONFIG_FILE), then environment variables, then command-line flags.
 type Config struct {
-	DBHost     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	DBPort     string
-	Port       string
+	Server   ServerConfig   `json:"server"`
+	Database DatabaseConfig `json:"database"`
+	JWT      JWTConfig      `json:"jwt"`
+	CORS     CORSConfig     `json:"cors"`
+	Catalog  CatalogConfig  `json:"catalog"`
+	Log      LogConfig      `json:"log"`
 }
 
-func LoadConfig() *Config {
-	godotenv.Load()
+type ServerConfig struct {
+	Port         string   `json:"port"`
+	ReadTimeout  Duration `json:"read_timeout"`
+	WriteTimeout Duration `json:"write_timeout"`
+	IdleTimeout  Duration `json:"idle_timeout"`
+	// MetricsAddr is the host:port serving /metrics, kept off the public API
+	MetricsAddr string `json:"metrics_addr"`
+	// DrainDelay is how long /readyz fails before the server stops accepting
+	// connections, so load balancers notice and stop routing to it
+	DrainDelay Duration `json:"drain_delay"`
+	// ShutdownTimeout is how long in-flight requests get to finish on SIGTERM
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+}
+
+type DatabaseConfig struct {
+	Host            string   `json:"host"`
+	Port            string   `json:"port"`
+	User            string   `json:"user"`
+	Password        string   `json:"password"`
+	Name            string   `json:"name"`
+	SSLMode         string   `json:"sslmode"`
+	MaxOpenConns    int      `json:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+	// AutoMigrate applies pending migrations when the server starts; when off
+	// the server refuses to start until `migrate up` has been run
+	AutoMigrate bool `json:"auto_migrate"`
+}
+
+// DSN is the libpq connection string for this database.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+type JWTConfig struct {
+	Secret          string   `json:"secret"`
+	AccessTokenTTL  Duration `json:"access_token_ttl"`
+	RefreshTokenTTL Duration `json:"refresh_token_ttl"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+type CatalogConfig struct {
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	Fixture  string `json:"fixture"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error; debug also logs every query
+	Level slog.Level `json:"level"`
+}
+
+// Duration reads "15m"-style strings from the config file.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func defaults() *Config {
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", ""),
-		DBName:     getEnv("DB_NAME", "reading_tracker"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		Port:       getEnv("PORT", "8080"),
+		Server: ServerConfig{
+			Port:            "8080",
+			MetricsAddr:     ":9090",
+			ReadTimeout:     Duration{15 * time.Second},
+			WriteTimeout:    Duration{60 * time.Second},
+			IdleTimeout:     Duration{120 * time.Second},
+			DrainDelay:      Duration{5 * time.Second},
+			ShutdownTimeout: Duration{20 * time.Second},
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "postgres",
+			Name:            "reading_tracker",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: Duration{30 * time.Minute},
+			AutoMigrate:     true,
+		},
+		JWT: JWTConfig{
+			AccessTokenTTL:  Duration{15 * time.Minute},
+			RefreshTokenTTL: Duration{30 * 24 * time.Hour},
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"http://localhost", "http://localhost:5173"},
+		},
+		Catalog: CatalogConfig{
+			Provider: "openlibrary",
+		},
+		Log: LogConfig{
+			Level: slog.LevelInfo,
+		},
+	}
+}
+
+// Load builds and validates the configuration; args are the command-line
+// arguments without the program name. Whatever follows the flags, such as a
+// subcommand, is returned as is.
+func Load(args []string) (*Config, []string, error) {
+	godotenv.Load()
+	cfg := defaults()
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a JSON config file")
+	port := fs.String("port", "", "HTTP port")
+	dbHost := fs.String("db-host", "", "database host")
+	dbPort := fs.String("db-port", "", "database port")
+	dbName := fs.String("db-name", "", "database name")
+	dbSSLMode := fs.String("db-sslmode", "", "database sslmode")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var errs []error
+	applyEnv(cfg, &errs)
+
+	setString(&cfg.Server.Port, *port)
+	setString(&cfg.Database.Host, *dbHost)
+	setString(&cfg.Database.Port, *dbPort)
+	setString(&cfg.Database.Name, *dbName)
+	setString(&cfg.Database.SSLMode, *dbSSLMode)
+
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return cfg, fs.Args(), nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config, errs *[]error) {
+	setString(&cfg.Server.Port, os.Getenv("PORT"))
+	setString(&cfg.Server.MetricsAddr, os.Getenv("METRICS_ADDR"))
+	envDuration(&cfg.Server.ReadTimeout, "SERVER_READ_TIMEOUT", errs)
+	envDuration(&cfg.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT", errs)
+	envDuration(&cfg.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT", errs)
+	envDuration(&cfg.Server.DrainDelay, "SERVER_DRAIN_DELAY", errs)
+	envDuration(&cfg.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT", errs)
+
+	setString(&cfg.Database.Host, os.Getenv("DB_HOST"))
+	setString(&cfg.Database.Port, os.Getenv("DB_PORT"))
+	setString(&cfg.Database.User, os.Getenv("DB_USER"))
+	setString(&cfg.Database.Password, os.Getenv("DB_PASSWORD"))
+	setString(&cfg.Database.Name, os.Getenv("DB_NAME"))
+	setString(&cfg.Database.SSLMode, os.Getenv("DB_SSLMODE"))
+	envInt(&cfg.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS", errs)
+	envInt(&cfg.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS", errs)
+	envDuration(&cfg.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME", errs)
+	envBool(&cfg.Database.AutoMigrate, "DB_AUTO_MIGRATE", errs)
+
+	setString(&cfg.JWT.Secret, os.Getenv("JWT_SECRET"))
+	envDuration(&cfg.JWT.AccessTokenTTL, "JWT_ACCESS_TOKEN_TTL", errs)
+	envDuration(&cfg.JWT.RefreshTokenTTL, "JWT_REFRESH_TOKEN_TTL", errs)
+
+	if origins := os.Getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		cfg.CORS.AllowedOrigins = nil
+		for _, o := range strings.Split(origins, ",") {
+			if o = strings.TrimSpace(o); o != "" {
+				cfg.CORS.AllowedOrigins = append(cfg.CORS.AllowedOrigins, o)
+			}
+		}
+	}
+
+	setString(&cfg.Catalog.Provider, os.Getenv("CATALOG_PROVIDER"))
+	setString(&cfg.Catalog.URL, os.Getenv("CATALOG_URL"))
+	setString(&cfg.Catalog.Fixture, os.Getenv("CATALOG_FIXTURE"))
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := cfg.Log.Level.UnmarshalText([]byte(level)); err != nil {
+			*errs = append(*errs, fmt.Errorf("LOG_LEVEL: %q is not debug, info, warn or error", level))
+		}
 	}
 }
 
-func getEnv(key, defaultValue string) string {
+func (c *Config) validate() []error {
+	var errs []error
+	if c.JWT.Secret == "" {
+		errs = append(errs, errors.New("JWT_SECRET is required"))
+	} else if len(c.JWT.Secret) < minJWTSecretLength {
+		errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d characters", minJWTSecretLength))
+	}
+	if c.JWT.AccessTokenTTL.Duration <= 0 || c.JWT.RefreshTokenTTL.Duration <= c.JWT.AccessTokenTTL.Duration {
+		errs = append(errs, errors.New("jwt: token TTLs must be positive and the refresh TTL longer than the access TTL"))
+	}
+	if c.Database.Host == "" || c.Database.User == "" || c.Database.Name == "" {
+		errs = append(errs, errors.New("database: host, user and name are required"))
+	}
+	if _, err := strconv.Atoi(c.Database.Port); err != nil {
+		errs = append(errs, fmt.Errorf("database: invalid port %q", c.Database.Port))
+	}
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("database: invalid sslmode %q", c.Database.SSLMode))
+	}
+	if c.Database.MaxOpenConns < 1 || c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database: max_open_conns must be at least 1 and max_idle_conns between 0 and max_open_conns"))
+	}
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("server: invalid port %q", c.Server.Port))
+	}
+	if _, port, err := net.SplitHostPort(c.Server.MetricsAddr); err != nil || port == c.Server.Port {
+		errs = append(errs, fmt.Errorf("server: invalid metrics_addr %q, use host:port on a port other than the API's", c.Server.MetricsAddr))
+	}
+	if c.Server.ReadTimeout.Duration <= 0 || c.Server.WriteTimeout.Duration <= 0 || c.Server.IdleTimeout.Duration <= 0 || c.Server.ShutdownTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("server: timeouts must be positive"))
+	}
+	if c.Server.DrainDelay.Duration < 0 {
+		errs = append(errs, errors.New("server: drain_delay cannot be negative"))
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("cors: at least one allowed origin is required"))
+	}
+	return errs
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func envInt(dst *int, key string, errs *[]error) {
 	value := os.Getenv(key)
 	if value == "" {
-		return defaultValue
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a number", key, value))
+		return
+	}
+	*dst = n
+}
+
+func envBool(dst *bool, key string, errs *[]error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not true or false", key, value))
+		return
+	}
+	*dst = b
+}
+
+func envDuration(dst *Duration, key string, errs *[]error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a duration like 30s or 15m", key, value))
+		return
 	}
-	return value
+	dst.Duration = d
 }
@@ -0,0 +1,233 @@
+package configs
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef"
+
+var configEnv = []string{
+	"CONFIG_FILE", "PORT", "METRICS_ADDR",
+	"SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT", "SERVER_DRAIN_DELAY", "SERVER_SHUTDOWN_TIMEOUT",
+	"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE",
+	"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_AUTO_MIGRATE",
+	"JWT_SECRET", "JWT_ACCESS_TOKEN_TTL", "JWT_REFRESH_TOKEN_TTL",
+	"CORS_ALLOWED_ORIGINS", "CATALOG_PROVIDER", "CATALOG_URL", "CATALOG_FIXTURE", "LOG_LEVEL",
+}
+
+// clearEnv blanks every variable Load reads, so the machine running the
+// tests cannot leak into them. Load treats an empty variable as unset.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range configEnv {
+		t.Setenv(key, "")
+	}
+}
+
+func writeConfigFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	cfg, rest, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Expected defaults to load, got %v", err)
+	}
+	if cfg.Server.Port != "8080" || cfg.Database.Host != "localhost" || cfg.Database.Port != "5432" {
+		t.Errorf("Unexpected defaults: server %+v, database %+v", cfg.Server, cfg.Database)
+	}
+	if cfg.JWT.AccessTokenTTL.Duration != 15*time.Minute {
+		t.Errorf("Expected a 15m access TTL, got %s", cfg.JWT.AccessTokenTTL)
+	}
+	if cfg.Log.Level != slog.LevelInfo {
+		t.Errorf("Expected info logging, got %s", cfg.Log.Level)
+	}
+	if len(rest) != 0 {
+		t.Errorf("Expected no leftover arguments, got %v", rest)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	path := writeConfigFile(t, `{
+		"server": {"port": "7000", "read_timeout": "5s"},
+		"database": {"host": "file-host", "name": "file-db", "port": "6000"},
+		"jwt": {"secret": "`+testSecret+`"}
+	}`)
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("DB_NAME", "env-db")
+	t.Setenv("PORT", "7500")
+
+	cfg, _, err := Load([]string{"-config", path, "-db-name", "flag-db"})
+	if err != nil {
+		t.Fatalf("Expected config to load, got %v", err)
+	}
+
+	// defaults < file
+	if cfg.Server.ReadTimeout.Duration != 5*time.Second {
+		t.Errorf("Expected the file's read timeout, got %s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Database.Port != "6000" {
+		t.Errorf("Expected the file's database port, got %s", cfg.Database.Port)
+	}
+	if cfg.Server.WriteTimeout.Duration != 60*time.Second {
+		t.Errorf("Expected the default write timeout, got %s", cfg.Server.WriteTimeout)
+	}
+	// file < env
+	if cfg.Database.Host != "env-host" || cfg.Server.Port != "7500" {
+		t.Errorf("Expected env to override the file, got host %s port %s", cfg.Database.Host, cfg.Server.Port)
+	}
+	// env < flags
+	if cfg.Database.Name != "flag-db" {
+		t.Errorf("Expected the flag to override env, got %s", cfg.Database.Name)
+	}
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, `{"server": {"port": "7000"}, "jwt": {"secret": "`+testSecret+`"}}`))
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Expected config to load, got %v", err)
+	}
+	if cfg.Server.Port != "7000" {
+		t.Errorf("Expected the port from CONFIG_FILE, got %s", cfg.Server.Port)
+	}
+}
+
+func TestLoad_ReturnsSubcommand(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	_, rest, err := Load([]string{"-port", "9000", "migrate", "up"})
+	if err != nil {
+		t.Fatalf("Expected config to load, got %v", err)
+	}
+	if strings.Join(rest, " ") != "migrate up" {
+		t.Errorf("Expected the subcommand back, got %v", rest)
+	}
+}
+
+func TestLoad_RejectsUnknownFileFields(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+	path := writeConfigFile(t, `{"server": {"prot": "7000"}}`)
+
+	_, _, err := Load([]string{"-config", path})
+	if err == nil || !strings.Contains(err.Error(), `unknown field "prot"`) {
+		t.Errorf("Expected the misspelt field to be rejected, got %v", err)
+	}
+}
+
+func TestLoad_RejectsMissingFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	_, _, err := Load([]string{"-config", filepath.Join(t.TempDir(), "missing.json")})
+	if err == nil || !strings.Contains(err.Error(), "reading config file") {
+		t.Errorf("Expected a missing file to be an error, got %v", err)
+	}
+}
+
+func TestLoad_RejectsBadFileDuration(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	for _, body := range []string{
+		`{"server": {"read_timeout": 30}}`,
+		`{"server": {"read_timeout": "soon"}}`,
+	} {
+		if _, _, err := Load([]string{"-config", writeConfigFile(t, body)}); err == nil {
+			t.Errorf("Expected %s to be rejected", body)
+		}
+	}
+}
+
+func TestLoad_RejectsUnknownFlag(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	if _, _, err := Load([]string{"-bogus"}); err == nil {
+		t.Error("Expected an unknown flag to be rejected")
+	}
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"missing secret", map[string]string{"JWT_SECRET": ""}, "JWT_SECRET is required"},
+		{"short secret", map[string]string{"JWT_SECRET": "short"}, "JWT_SECRET must be at least 16 characters"},
+		{"refresh not longer than access", map[string]string{"JWT_ACCESS_TOKEN_TTL": "1h", "JWT_REFRESH_TOKEN_TTL": "1h"}, "jwt: token TTLs"},
+		{"bad env duration", map[string]string{"SERVER_READ_TIMEOUT": "soon"}, `SERVER_READ_TIMEOUT: "soon" is not a duration`},
+		{"zero timeout", map[string]string{"SERVER_SHUTDOWN_TIMEOUT": "0s"}, "server: timeouts must be positive"},
+		{"negative drain delay", map[string]string{"SERVER_DRAIN_DELAY": "-1s"}, "server: drain_delay cannot be negative"},
+		{"bad server port", map[string]string{"PORT": "http"}, `server: invalid port "http"`},
+		{"bad metrics address", map[string]string{"METRICS_ADDR": "9090"}, `server: invalid metrics_addr "9090"`},
+		{"metrics on the API port", map[string]string{"METRICS_ADDR": ":8080"}, `server: invalid metrics_addr ":8080"`},
+		{"bad database port", map[string]string{"DB_PORT": "pg"}, `database: invalid port "pg"`},
+		{"bad sslmode", map[string]string{"DB_SSLMODE": "sometimes"}, `database: invalid sslmode "sometimes"`},
+		{"bad int", map[string]string{"DB_MAX_OPEN_CONNS": "many"}, `DB_MAX_OPEN_CONNS: "many" is not a number`},
+		{"idle above open", map[string]string{"DB_MAX_OPEN_CONNS": "2", "DB_MAX_IDLE_CONNS": "3"}, "database: max_open_conns"},
+		{"bad bool", map[string]string{"DB_AUTO_MIGRATE": "sometimes"}, `DB_AUTO_MIGRATE: "sometimes" is not true or false`},
+		{"no origins", map[string]string{"CORS_ALLOWED_ORIGINS": " , "}, "cors: at least one allowed origin"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, `LOG_LEVEL: "loud"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("JWT_SECRET", testSecret)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, _, err := Load(nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_ReportsEveryError(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "http")
+	t.Setenv("DB_SSLMODE", "sometimes")
+
+	_, _, err := Load(nil)
+	if err == nil {
+		t.Fatal("Expected the configuration to be rejected")
+	}
+	for _, want := range []string{"JWT_SECRET is required", "server: invalid port", "database: invalid sslmode"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestLoad_ErrorDoesNotEchoSecret(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", "tooshort")
+
+	_, _, err := Load(nil)
+	if err == nil || strings.Contains(err.Error(), "tooshort") {
+		t.Errorf("Expected an error that does not include the secret, got %v", err)
+	}
+}
@@ -1,5 +1,6 @@
 import { useState } from "react";
 import { Link, useNavigate, useLocation } from "react-router-dom";
+import api from "../services/api";
 import {
   Menu,
   X,
@@ -15,8 +16,14 @@ function Navbar() {
   const location = useLocation();
   const [isOpen, setIsOpen] = useState(false);
 
-  const handleLogout = () => {
+  const handleLogout = async () => {
+    try {
+      await api.post("/logout");
+    } catch {
+      // the session may already be gone; clear it locally either way
+    }
     localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
     navigate("/login");
   };
 
@@ -20,6 +20,27 @@ function AddBook() {
     setFormData({ ...formData, [e.target.name]: e.target.value });
   };
 
+  // fills empty fields from the catalog, never overwrites what was typed
+  const handleLookup = async () => {
+    if (!formData.isbn.trim()) return;
+    setError("");
+    try {
+      const { data } = await api.get("/lookup", {
+        params: { isbn: formData.isbn },
+      });
+      setFormData((prev) => ({
+        ...prev,
+        title: prev.title || data.title || "",
+        author: prev.author || data.author || "",
+        genre: prev.genre || data.genre || "",
+        publication_year: prev.publication_year || data.publication_year || "",
+        total_pages: prev.total_pages || data.total_pages || "",
+      }));
+    } catch (err) {
+      setError(err.response?.data?.error || "No details found for this ISBN.");
+    }
+  };
+
   const validateForm = () => {
     const { title, author, total_pages, publication_year } = formData;
     const currentYear = 2026;
@@ -163,6 +184,13 @@ function AddBook() {
                     className="w-full px-6 py-4 bg-slate-50 border-none rounded-2xl focus:ring-2 focus:ring-blue-500 outline-none transition font-bold text-slate-700"
                     placeholder="International Standard Book Number"
                   />
+                  <button
+                    type="button"
+                    onClick={handleLookup}
+                    className="mt-3 ml-1 text-xs font-black text-blue-600 uppercase tracking-[0.2em] hover:text-blue-800"
+                  >
+                    Fill details from ISBN
+                  </button>
                 </div>
               </div>
 
@@ -21,6 +21,7 @@ function Login() {
       const response = await api.post("/login", formData);
       const token = response.data.token;
       localStorage.setItem("token", token);
+      localStorage.setItem("refresh_token", response.data.refresh_token);
       navigate("/dashboard");
     } catch (err) {
       setError(err.response?.data?.error || "Invalid email or password");
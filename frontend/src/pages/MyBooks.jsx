@@ -26,8 +26,16 @@ function MyBooks() {
 
   const fetchBooks = async () => {
     try {
-      const response = await api.get("/books");
-      setBooks(response.data.data || []);
+      let all = [];
+      let cursor = "";
+      do {
+        const response = await api.get("/books", {
+          params: { limit: 100, cursor: cursor || undefined },
+        });
+        all = all.concat(response.data.data || []);
+        cursor = response.data.next_cursor;
+      } while (cursor);
+      setBooks(all);
       setLoading(false);
     } catch (err) {
       setError("Unable to sync your library. Please try again.");
@@ -71,6 +79,8 @@ function MyBooks() {
       Finished: "bg-emerald-50 text-emerald-600 border-emerald-100",
       Reading: "bg-indigo-50 text-indigo-600 border-indigo-100",
       "Currently Reading": "bg-indigo-50 text-indigo-600 border-indigo-100",
+      Paused: "bg-amber-50 text-amber-600 border-amber-100",
+      "Did Not Finish": "bg-rose-50 text-rose-600 border-rose-100",
       Default: "bg-slate-50 text-slate-400 border-slate-100",
     };
 
@@ -16,24 +16,34 @@ function Review() {
   const navigate = useNavigate();
 
   const [reviews, setReviews] = useState([]);
+  const [summary, setSummary] = useState(null);
+  const [sort, setSort] = useState("newest");
+  const [editingId, setEditingId] = useState(null);
   const [hasUserReviewed, setHasUserReviewed] = useState(false);
-  const [formData, setFormData] = useState({ rating: 5, comment: "" });
+  const [formData, setFormData] = useState({
+    rating: 5,
+    comment: "",
+    visibility: "",
+    is_spoiler: false,
+  });
+  const [showSpoilers, setShowSpoilers] = useState(false);
   const [error, setError] = useState("");
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
     fetchReviews();
-  }, [id]);
+  }, [id, sort, showSpoilers]);
 
   const fetchReviews = async () => {
     try {
-      const response = await api.get(`/books/${id}/reviews`);
+      const response = await api.get(`/books/${id}/reviews`, {
+        params: { sort, limit: 50, show_spoilers: showSpoilers },
+      });
       const allReviews = response.data.data || [];
       setReviews(allReviews);
+      setSummary(response.data.summary);
 
-      const userReviewExists = allReviews.some(
-        (rev) => rev.book_id === parseInt(id)
-      );
+      const userReviewExists = allReviews.some((rev) => rev.is_mine);
       setHasUserReviewed(userReviewExists);
     } catch (err) {
       console.error("No reviews found yet.");
@@ -46,18 +56,60 @@ function Review() {
   const handleSubmit = async (e) => {
     e.preventDefault();
     setError("");
+    const payload = {
+      rating: parseInt(formData.rating),
+      comment: formData.comment,
+      is_spoiler: formData.is_spoiler,
+    };
+    if (formData.visibility) payload.visibility = formData.visibility;
     try {
-      await api.post(`/books/${id}/reviews`, {
-        rating: parseInt(formData.rating),
-        comment: formData.comment,
-      });
-      alert("Your review has been shared with the community!");
+      if (editingId) {
+        await api.put(`/books/${id}/reviews/${editingId}`, payload);
+        setEditingId(null);
+      } else {
+        await api.post(`/books/${id}/reviews`, payload);
+        alert("Your review has been shared with the community!");
+      }
       fetchReviews();
     } catch (err) {
       setError(err.response?.data?.error || "Unable to submit review.");
     }
   };
 
+  const startEdit = (rev) => {
+    setFormData({
+      rating: rev.rating,
+      comment: rev.comment,
+      visibility: "",
+      is_spoiler: rev.is_spoiler,
+    });
+    setEditingId(rev.id);
+  };
+
+  const handleDelete = async (rev) => {
+    if (!window.confirm("Delete your review?")) return;
+    try {
+      await api.delete(`/books/${id}/reviews/${rev.id}`);
+      setFormData({ rating: 5, comment: "", visibility: "", is_spoiler: false });
+      fetchReviews();
+    } catch (err) {
+      setError(err.response?.data?.error || "Unable to delete review.");
+    }
+  };
+
+  const toggleHelpful = async (rev) => {
+    try {
+      if (rev.voted_helpful) {
+        await api.delete(`/reviews/${rev.id}/helpful`);
+      } else {
+        await api.post(`/reviews/${rev.id}/helpful`);
+      }
+      fetchReviews();
+    } catch (err) {
+      console.error(err.response?.data?.error || "Unable to vote.");
+    }
+  };
+
   if (loading) {
     return (
       <div className="min-h-screen bg-[#F8FAFC] flex flex-col items-center justify-center">
@@ -96,14 +148,14 @@ function Review() {
 
         <div className="grid grid-cols-1 lg:grid-cols-2 gap-12">
           <div className="h-fit">
-            {!hasUserReviewed ? (
+            {!hasUserReviewed || editingId ? (
               <div className="bg-white p-10 rounded-[3rem] shadow-sm border border-slate-100">
                 <div className="flex items-center space-x-3 mb-8">
                   <div className="p-3 bg-blue-50 rounded-2xl">
                     <MessageSquare className="w-6 h-6 text-blue-600" />
                   </div>
                   <h2 className="text-xl font-black text-slate-800 uppercase tracking-tighter">
-                    Write your Review
+                    {editingId ? "Edit your Review" : "Write your Review"}
                   </h2>
                 </div>
 
@@ -149,11 +201,41 @@ function Review() {
                     ></textarea>
                   </div>
 
+                  <div className="flex items-center justify-between gap-4">
+                    <select
+                      value={formData.visibility}
+                      onChange={(e) =>
+                        setFormData({ ...formData, visibility: e.target.value })
+                      }
+                      className="px-4 py-3 bg-slate-50 border-none rounded-2xl outline-none text-xs font-bold text-slate-600 cursor-pointer"
+                    >
+                      <option value="">
+                        {editingId ? "Keep visibility" : "Default visibility"}
+                      </option>
+                      <option value="public">Public</option>
+                      <option value="followers">Followers only</option>
+                      <option value="private">Only me</option>
+                    </select>
+                    <label className="flex items-center gap-2 text-xs font-bold text-slate-500">
+                      <input
+                        type="checkbox"
+                        checked={formData.is_spoiler}
+                        onChange={(e) =>
+                          setFormData({
+                            ...formData,
+                            is_spoiler: e.target.checked,
+                          })
+                        }
+                      />
+                      Contains spoilers
+                    </label>
+                  </div>
+
                   <button
                     type="submit"
                     className="w-full bg-slate-900 hover:bg-blue-600 text-white font-black text-xs uppercase tracking-widest py-5 rounded-2xl shadow-xl transition-all active:scale-95"
                   >
-                    Post Review
+                    {editingId ? "Save Changes" : "Post Review"}
                   </button>
                 </form>
               </div>
@@ -184,8 +266,29 @@ function Review() {
               <h2 className="text-[10px] font-black text-slate-300 uppercase tracking-[0.3em]">
                 Recent Reader Feedback
               </h2>
-              <div className="flex items-center gap-1 text-blue-500 font-bold text-[10px] uppercase tracking-widest">
-                <Users size={14} /> {reviews.length} Total
+              <div className="flex items-center gap-4">
+                <label className="flex items-center gap-1 text-[10px] font-black text-slate-400 uppercase tracking-widest">
+                  <input
+                    type="checkbox"
+                    checked={showSpoilers}
+                    onChange={(e) => setShowSpoilers(e.target.checked)}
+                  />
+                  Spoilers
+                </label>
+                <select
+                  value={sort}
+                  onChange={(e) => setSort(e.target.value)}
+                  className="bg-transparent text-[10px] font-black text-slate-400 uppercase tracking-widest outline-none"
+                >
+                  <option value="newest">Newest</option>
+                  <option value="helpful">Most Helpful</option>
+                </select>
+                <div className="flex items-center gap-1 text-blue-500 font-bold text-[10px] uppercase tracking-widest">
+                  <Users size={14} /> {summary?.rating_count ?? reviews.length}{" "}
+                  Total
+                  {summary?.rating_count > 0 &&
+                    ` · ${summary.average_rating.toFixed(1)} ★`}
+                </div>
               </div>
             </div>
 
@@ -193,7 +296,7 @@ function Review() {
               <div className="bg-white p-20 rounded-[3rem] border border-dashed border-slate-200 text-center flex flex-col items-center">
                 <Star size={48} className="text-slate-100 mb-4" />
                 <p className="text-slate-400 font-medium italic">
-                  No reviews match this ISBN yet.
+                  No reviews for this book yet.
                 </p>
                 <p className="text-slate-300 text-[10px] mt-2 uppercase font-bold tracking-widest">
                   Be the first to rate it
@@ -214,32 +317,78 @@ function Review() {
                             {"★".repeat(5 - rev.rating)}
                           </span>
                         </div>
-                        {rev.book_id === parseInt(id) && (
-                          <span className="bg-blue-50 text-blue-600 text-[9px] font-black uppercase px-2 py-1 rounded-md">
-                            My Review
-                          </span>
-                        )}
+                        <div className="flex items-center gap-2">
+                          {rev.edited_at && (
+                            <span className="text-slate-300 text-[9px] font-black uppercase">
+                              Edited
+                            </span>
+                          )}
+                          {rev.is_mine && (
+                            <span className="bg-blue-50 text-blue-600 text-[9px] font-black uppercase px-2 py-1 rounded-md">
+                              My Review
+                            </span>
+                          )}
+                        </div>
                       </div>
 
-                      <p className="text-slate-700 leading-relaxed font-serif text-lg italic">
-                        "{rev.comment}"
-                      </p>
+                      {rev.spoiler_hidden ? (
+                        <button
+                          onClick={() => setShowSpoilers(true)}
+                          className="text-slate-400 text-sm font-bold italic hover:text-blue-500"
+                        >
+                          This review contains spoilers. Show anyway?
+                        </button>
+                      ) : (
+                        <p className="text-slate-700 leading-relaxed font-serif text-lg italic">
+                          "{rev.comment}"
+                        </p>
+                      )}
 
                       <div className="mt-6 pt-6 border-t border-slate-50 flex items-center justify-between">
                         <div className="flex items-center gap-2">
                           <div className="w-7 h-7 bg-blue-100 rounded-full flex items-center justify-center text-[10px] font-black text-blue-600 uppercase">
-                            {rev.book?.user?.name
-                              ? rev.book.user.name.charAt(0)
+                            {rev.reviewer_name
+                              ? rev.reviewer_name.charAt(0)
                               : "U"}
                           </div>
                           <span className="text-[10px] font-black text-slate-800 uppercase tracking-widest">
-                            {rev.book?.user?.name || "Verified Reader"}
+                            {rev.reviewer_name || "Verified Reader"}
                           </span>
                         </div>
 
-                        <span className="text-[10px] font-bold text-slate-300 uppercase tracking-tighter">
-                          {new Date(rev.created_at).toLocaleDateString()}
-                        </span>
+                        <div className="flex items-center gap-4">
+                          {rev.is_mine && (
+                            <>
+                              <button
+                                onClick={() => startEdit(rev)}
+                                className="text-[10px] font-black uppercase tracking-widest text-slate-300 hover:text-blue-500"
+                              >
+                                Edit
+                              </button>
+                              <button
+                                onClick={() => handleDelete(rev)}
+                                className="text-[10px] font-black uppercase tracking-widest text-slate-300 hover:text-red-500"
+                              >
+                                Delete
+                              </button>
+                            </>
+                          )}
+                          {!rev.is_mine && (
+                            <button
+                              onClick={() => toggleHelpful(rev)}
+                              className={`text-[10px] font-black uppercase tracking-widest ${
+                                rev.voted_helpful
+                                  ? "text-blue-600"
+                                  : "text-slate-300 hover:text-blue-500"
+                              }`}
+                            >
+                              Helpful ({rev.helpful_count})
+                            </button>
+                          )}
+                          <span className="text-[10px] font-bold text-slate-300 uppercase tracking-tighter">
+                            {new Date(rev.created_at).toLocaleDateString()}
+                          </span>
+                        </div>
                       </div>
                     </div>
                   </div>
@@ -165,7 +165,9 @@ function UpdateProgress() {
                   <option value="Currently Reading">
                     📖 Currently Reading
                   </option>
+                  <option value="Paused">⏸️ Paused</option>
                   <option value="Finished">✅ Finished</option>
+                  <option value="Did Not Finish">🚫 Did Not Finish</option>
                 </select>
               </div>
 
@@ -12,4 +12,54 @@ api.interceptors.request.use((config) => {
   return config;
 });
 
+// a refresh token can only be used once, so every 401 that arrives while a
+// refresh is running waits for that refresh instead of starting its own
+let refreshing = null;
+
+const refreshTokens = (refreshToken) => {
+  if (!refreshing) {
+    refreshing = api
+      .post("/token/refresh", { refresh_token: refreshToken })
+      .then(({ data }) => {
+        localStorage.setItem("token", data.token);
+        localStorage.setItem("refresh_token", data.refresh_token);
+      })
+      .catch((refreshError) => {
+        localStorage.removeItem("token");
+        localStorage.removeItem("refresh_token");
+        throw refreshError;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+};
+
+// access tokens are short-lived, so swap the refresh token for a new pair once
+api.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const original = error.config;
+    const refreshToken = localStorage.getItem("refresh_token");
+
+    if (
+      error.response?.status !== 401 ||
+      original._retry ||
+      !refreshToken ||
+      original.url === "/token/refresh"
+    ) {
+      return Promise.reject(error);
+    }
+
+    original._retry = true;
+    // a refresh that finished after this request was sent already fixed it
+    const token = localStorage.getItem("token");
+    if (original.headers.Authorization === `Bearer ${token}`) {
+      await refreshTokens(refreshToken);
+    }
+    return api(original);
+  },
+);
+
 export default api;